@@ -0,0 +1,85 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AdminCacheTTL is the default time the chat admins are cached for,
+// see Settings.AdminCacheTTL.
+const AdminCacheTTL = 5 * time.Minute
+
+// AdminCacheNamespace is the Store namespace of the cached chat admins.
+const AdminCacheNamespace = "admins"
+
+// CachedAdminsOf works like AdminsOf, but keeps the admins in the bot
+// Store for Settings.AdminCacheTTL, so the moderation checks don't call
+// the API on every message. The cache of the chat is dropped on the
+// chat_member and my_chat_member updates changing an admin.
+func (b *Bot) CachedAdminsOf(chat *Chat) ([]ChatMember, error) {
+	key := chat.Recipient()
+
+	data, err := b.Store().Get(AdminCacheNamespace, key)
+	if err == nil {
+		var admins []ChatMember
+		if err := json.Unmarshal(data, &admins); err == nil {
+			return admins, nil
+		}
+	} else if !errors.Is(err, ErrStoreKeyNotFound) {
+		return nil, err
+	}
+
+	admins, err := b.AdminsOf(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(admins)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if err := b.Store().Set(AdminCacheNamespace, key, data, b.adminCacheTTL); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// InvalidateAdmins drops the cached admins of the chat, see CachedAdminsOf.
+func (b *Bot) InvalidateAdmins(chat *Chat) error {
+	return b.Store().Delete(AdminCacheNamespace, chat.Recipient())
+}
+
+// IsChatAdmin reports whether the user is the creator or an admin
+// of the chat, using CachedAdminsOf.
+func (b *Bot) IsChatAdmin(chat *Chat, user *User) (bool, error) {
+	admins, err := b.CachedAdminsOf(chat)
+	if err != nil {
+		return false, err
+	}
+	return hasAdmin(admins, user), nil
+}
+
+func hasAdmin(admins []ChatMember, user *User) bool {
+	for _, admin := range admins {
+		if admin.User != nil && admin.User.ID == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidateAdmins drops the cached admins of the chat
+// if the member update promotes or demotes an admin.
+func (b *Bot) invalidateAdmins(u *ChatMemberUpdate) {
+	if u.Chat == nil || !isAdminRole(u.OldChatMember) && !isAdminRole(u.NewChatMember) {
+		return
+	}
+	if err := b.InvalidateAdmins(u.Chat); err != nil {
+		b.OnError(err, nil)
+	}
+}
+
+func isAdminRole(m *ChatMember) bool {
+	return m != nil && (m.Role == Creator || m.Role == Administrator)
+}
@@ -1,9 +1,11 @@
 package telebot
 
 import (
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEmbedRights(t *testing.T) {
@@ -46,3 +48,53 @@ func TestEmbedRights(t *testing.T) {
 	}
 	assert.Equal(t, expected, params)
 }
+
+func TestCachedAdmins(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestAPI(t, func(call apiCall) string {
+		calls.Add(1)
+		return `{"ok":true,"result":[{"status":"creator","user":{"id":1}},{"status":"administrator","user":{"id":2}}]}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	var (
+		isAdmin  bool
+		adminErr error
+	)
+	b.Handle(OnText, func(c Context) error {
+		isAdmin, adminErr = c.SenderIsAdmin()
+		return nil
+	})
+
+	group := &Chat{ID: -100, Type: ChatSuperGroup}
+	send := func(m *Message) {
+		m.Text, m.Chat = "hi", group
+		b.ProcessUpdate(Update{Message: m})
+		require.NoError(t, adminErr)
+	}
+
+	send(&Message{Sender: &User{ID: 2}})
+	assert.True(t, isAdmin)
+	send(&Message{Sender: &User{ID: 3}})
+	assert.False(t, isAdmin)
+	assert.EqualValues(t, 1, calls.Load())
+
+	send(&Message{Sender: &User{ID: 1087968824}, SenderChat: group})
+	assert.True(t, isAdmin)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// Promotion drops the cache
+	b.ProcessUpdate(Update{ChatMember: &ChatMemberUpdate{
+		Chat:          group,
+		OldChatMember: &ChatMember{Role: Member, User: &User{ID: 3}},
+		NewChatMember: &ChatMember{Role: Administrator, User: &User{ID: 3}},
+	}})
+	send(&Message{Sender: &User{ID: 2}})
+	assert.EqualValues(t, 2, calls.Load())
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "hi", Chat: &Chat{ID: 2, Type: ChatPrivate}, Sender: &User{ID: 2}}})
+	assert.False(t, isAdmin)
+	assert.EqualValues(t, 2, calls.Load())
+}
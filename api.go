@@ -41,6 +41,7 @@ type API interface {
 	DeleteStickerSet(name string) error
 	DeleteTopic(chat *Chat, topic *Topic) error
 	Download(file *File, localFilename string) error
+	DownloadTo(file *File, w io.Writer, progress ...DownloadProgress) error
 	Edit(msg Editable, what any, opts ...any) (*Message, error)
 	EditCaption(msg Editable, caption string, opts ...any) (*Message, error)
 	EditGeneralTopic(chat *Chat, topic *Topic) error
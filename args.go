@@ -0,0 +1,190 @@
+package telebot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArgError is returned by BindArgs when the arguments don't fit the
+// struct. Its message is meant to be replied to the user as is:
+//
+//	if err := c.BindArgs(&args); err != nil {
+//		var argErr *tele.ArgError
+//		if errors.As(err, &argErr) {
+//			return c.Reply(argErr.Error())
+//		}
+//		return err
+//	}
+type ArgError struct {
+	// Name is the name of the argument, empty for the extra arguments.
+	Name string
+
+	// Value is the invalid value, empty if the argument is missing.
+	Value string
+
+	// Reason describes what's wrong with the value.
+	Reason string
+}
+
+// Error implements error interface.
+func (err *ArgError) Error() string {
+	switch {
+	case err.Name == "":
+		return err.Reason
+	case err.Value == "":
+		return fmt.Sprintf("%s %s", err.Name, err.Reason)
+	default:
+		return fmt.Sprintf("%s %s, got %q", err.Name, err.Reason, err.Value)
+	}
+}
+
+// BindArgs parses the args into the fields of the struct dest points to,
+// one argument per exported field in the order of declaration. The
+// supported field types are string, bool, the integers, the floats,
+// time.Duration and []string, which takes the rest of the args.
+//
+// The arg tag sets the name of the argument used in the errors and the
+// options: optional for the fields which may be missing, rest for a
+// string field taking the rest of the args joined with spaces. The "-"
+// tag skips the field:
+//
+//	var args struct {
+//		User   int64         `arg:"user"`
+//		For    time.Duration `arg:"duration,optional"`
+//		Reason string        `arg:"reason,optional,rest"`
+//	}
+//
+// The arguments which can't be parsed are reported with ArgError.
+func BindArgs(args []string, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("telebot: BindArgs needs a pointer to struct, got %T", dest)
+	}
+	v = v.Elem()
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("arg")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		optional := hasTagOption(opts, "optional")
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String {
+			fv.Set(reflect.ValueOf(append([]string(nil), args...)).Convert(fv.Type()))
+			if len(args) == 0 && !optional {
+				return &ArgError{Name: name, Reason: "is missing"}
+			}
+			return nil
+		}
+
+		if len(args) == 0 {
+			if optional {
+				continue
+			}
+			return &ArgError{Name: name, Reason: "is missing"}
+		}
+
+		arg := args[0]
+		args = args[1:]
+		if fv.Kind() == reflect.String && hasTagOption(opts, "rest") {
+			arg = strings.Join(append([]string{arg}, args...), " ")
+			args = nil
+		}
+
+		if err := setArg(fv, arg); err != nil {
+			var argErr *ArgError
+			if errors.As(err, &argErr) {
+				argErr.Name, argErr.Value = name, arg
+			}
+			return err
+		}
+	}
+
+	if len(args) > 0 {
+		return &ArgError{Reason: fmt.Sprintf("unexpected argument %q", args[0])}
+	}
+	return nil
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setArg parses the argument into the field.
+func setArg(fv reflect.Value, arg string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return &ArgError{Reason: "must be a duration like 10m or 1h30m"}
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(arg)
+	case reflect.Bool:
+		b, ok := parseArgBool(arg)
+		if !ok {
+			return &ArgError{Reason: "must be yes or no"}
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(arg, 10, fv.Type().Bits())
+		if err != nil {
+			return &ArgError{Reason: "must be a whole number"}
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(arg, 10, fv.Type().Bits())
+		if err != nil {
+			return &ArgError{Reason: "must be a positive whole number"}
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(arg, fv.Type().Bits())
+		if err != nil {
+			return &ArgError{Reason: "must be a number"}
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("telebot: BindArgs doesn't support fields of type %s", fv.Type())
+	}
+	return nil
+}
+
+func parseArgBool(arg string) (value, ok bool) {
+	switch strings.ToLower(arg) {
+	case "yes", "y", "on":
+		return true, true
+	case "no", "n", "off":
+		return false, true
+	}
+	b, err := strconv.ParseBool(arg)
+	return b, err == nil
+}
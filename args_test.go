@@ -0,0 +1,64 @@
+package telebot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindArgs(t *testing.T) {
+	type banArgs struct {
+		User    int64         `arg:"user"`
+		For     time.Duration `arg:"duration,optional"`
+		Silent  bool          `arg:"silent,optional"`
+		Reason  string        `arg:"reason,optional,rest"`
+		skipped string
+	}
+
+	var args banArgs
+	require.NoError(t, BindArgs([]string{"42", "1h", "yes", "too", "much", "spam"}, &args))
+	assert.Equal(t, banArgs{User: 42, For: time.Hour, Silent: true, Reason: "too much spam"}, args)
+
+	args = banArgs{}
+	require.NoError(t, BindArgs([]string{"42"}, &args))
+	assert.Equal(t, banArgs{User: 42}, args)
+
+	var argErr *ArgError
+	err := BindArgs(nil, &args)
+	require.True(t, errors.As(err, &argErr))
+	assert.Equal(t, "user is missing", err.Error())
+
+	err = BindArgs([]string{"bob"}, &args)
+	require.True(t, errors.As(err, &argErr))
+	assert.Equal(t, "user", argErr.Name)
+	assert.Equal(t, `user must be a whole number, got "bob"`, err.Error())
+
+	err = BindArgs([]string{"42", "soon"}, &args)
+	assert.Equal(t, `duration must be a duration like 10m or 1h30m, got "soon"`, err.Error())
+
+	var pair struct {
+		Price float64
+		Tags  []string
+	}
+	require.NoError(t, BindArgs([]string{"9.5", "a", "b"}, &pair))
+	assert.Equal(t, 9.5, pair.Price)
+	assert.Equal(t, []string{"a", "b"}, pair.Tags)
+
+	err = BindArgs([]string{"9.5"}, &pair)
+	assert.Equal(t, "tags is missing", err.Error())
+
+	var one struct{ N uint8 }
+	assert.Equal(t, `unexpected argument "2"`, BindArgs([]string{"1", "2"}, &one).Error())
+	assert.Equal(t, `n must be a positive whole number, got "300"`, BindArgs([]string{"300"}, &one).Error())
+
+	assert.Error(t, BindArgs(nil, one))
+	assert.Error(t, BindArgs([]string{"x"}, &struct{ M map[string]int }{}))
+
+	c := NewContext(nil, Update{Message: &Message{Text: "/ban 7 10m", Payload: "7 10m"}})
+	args = banArgs{}
+	require.NoError(t, c.BindArgs(&args))
+	assert.Equal(t, banArgs{User: 7, For: 10 * time.Minute}, args)
+}
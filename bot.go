@@ -3,12 +3,14 @@ package telebot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -33,9 +35,13 @@ func NewBot(pref Settings) (*Bot, error) {
 	if pref.Poller == nil {
 		pref.Poller = &LongPoller{}
 	}
-	if pref.OnError == nil {
+	defaultedOnError := pref.OnError == nil
+	if defaultedOnError {
 		pref.OnError = defaultOnError
 	}
+	if pref.Clock == nil {
+		pref.Clock = realClock{}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -45,8 +51,15 @@ func NewBot(pref Settings) (*Bot, error) {
 		Poller:  pref.Poller,
 		onError: pref.OnError,
 
+		onUnmatched: pref.OnUnmatched,
+
 		Updates:  make(chan Update, pref.Updates),
 		handlers: make(map[string]HandlerFunc),
+		botState: &botState{
+			albums:   make(map[string]*albumBuffer),
+			store:    pref.Store,
+			sessions: pref.SessionStore,
+		},
 
 		synchronous: pref.Synchronous,
 		verbose:     pref.Verbose,
@@ -56,23 +69,90 @@ func NewBot(pref Settings) (*Bot, error) {
 		rootCtx:        ctx,
 		cancel:         cancel,
 		handlerTimeout: pref.HandlerTimeout,
+		editFallback:   pref.EditFallbackToSend,
+		albumTimeout:   pref.AlbumTimeout,
+		priority:       DefaultHandlerPriority,
+		clock:          pref.Clock,
+
+		urlUploadFallback: pref.URLUploadFallback,
+		correlateLogs:     pref.CorrelateLogs,
+		foldCommands:      pref.CaseInsensitiveCommands,
+		sessionKey:        pref.SessionKey,
+		sessionTTL:        pref.SessionTTL,
+		adminCacheTTL:     pref.AdminCacheTTL,
+		defaultLocale:     pref.DefaultLocale,
+	}
+
+	if bot.adminCacheTTL == 0 {
+		bot.adminCacheTTL = AdminCacheTTL
+	}
+
+	if pref.HandlerPriority != nil {
+		if err := validatePriority(pref.HandlerPriority); err != nil {
+			return nil, err
+		}
+		bot.priority = pref.HandlerPriority
+	}
+
+	if pref.MaxConcurrentUploads > 0 {
+		bot.uploads = make(chan struct{}, pref.MaxConcurrentUploads)
 	}
 
 	// Initialize logger
 	if pref.Log != nil {
 		bot.logger = NewLogger(*pref.Log)
+		bot.traceAPI = pref.Log.Enable && pref.Log.TraceAPI
 	} else {
 		bot.logger = NewNoOpLogger()
 	}
 
+	bot.output = bot.logger
+	if pref.Log == nil || !pref.Log.DisableRedaction {
+		bot.redactor = &redactor{}
+		bot.redactor.add(pref.Token)
+		if pref.Log != nil {
+			bot.redactor.add(pref.Log.Secrets...)
+		}
+		if hook, ok := pref.Poller.(*Webhook); ok {
+			bot.redactor.add(hook.SecretToken)
+		}
+		bot.output = &redactingLogger{Logger: bot.logger, r: bot.redactor}
+
+		// The default one prints the errors as is.
+		if defaultedOnError {
+			bot.onError = func(err error, c Context) {
+				defaultOnError(bot.redactor.redactError(err), c)
+			}
+		}
+	}
+	if pref.Log != nil {
+		bot.logLevels = pref.Log.Levels
+	}
+	bot.pollLogger = NewSampledLogger(bot.Logger("poller"), 1)
+	if pref.Log != nil && pref.Log.DumpUpdates {
+		bot.dump = newDumper(bot.Logger("dump"), pref.Log.DumpFilter)
+	}
+
+	if pref.UpdateHistory > 0 {
+		bot.history = newUpdateHistory(pref.UpdateHistory, pref.RedactUpdate)
+	}
+	if pref.TrackUnhandled {
+		bot.unhandled = newUnhandledTypes(bot.output)
+	}
+
+	if pref.SendRate != nil {
+		bot.sendRate = newSendGovernor(*pref.SendRate, bot.output, bot.clock)
+	}
+	if pref.RateWarningThreshold > 0 {
+		bot.sendMeter = newSendMeter(pref.RateWarningThreshold, pref.OnRateWarning, bot.output, bot.clock)
+	}
+
 	if pref.Offline {
-		bot.Me = &User{}
+		bot.setIdentity(&User{})
 	} else {
-		user, err := bot.getMe()
-		if err != nil {
+		if _, err := bot.RefreshIdentity(); err != nil {
 			return nil, err
 		}
-		bot.Me = user
 	}
 
 	bot.group = bot.Group()
@@ -81,6 +161,10 @@ func NewBot(pref Settings) (*Bot, error) {
 
 // Bot represents a separate Telegram bot instance.
 type Bot struct {
+	// Me is the bot user, which is an empty stub for an offline bot.
+	// Use Identity to read it concurrently with RefreshIdentity, or
+	// from the handlers, whose copies of the bot see the refreshed
+	// identity through it.
 	Me      *User
 	Token   string
 	URL     string
@@ -88,8 +172,15 @@ type Bot struct {
 	Poller  Poller
 	onError func(error, Context)
 
+	// onUnmatched is called with the updates no handler matched.
+	onUnmatched func(Context)
+
 	group       *Group
 	handlers    map[string]HandlerFunc
+	chatRoutes  map[string][]chatRoute
+	prefixes    []prefixRoute
+	regexps     []regexpRoute
+	patterns    []patternRoute
 	synchronous bool
 	verbose     bool
 	parseMode   ParseMode
@@ -98,10 +189,138 @@ type Bot struct {
 	// Context-based lifecycle management
 	rootCtx context.Context
 	cancel  context.CancelFunc
-	wg      sync.WaitGroup
+
+	// reqCtx is the context of the requests of the bot bound
+	// to an update context, see Context.Ctx.
+	reqCtx context.Context
 
 	handlerTimeout time.Duration
 	logger         Logger
+
+	editFallback bool
+	priority     []RouteKind
+	clock        Clock
+
+	albumTimeout time.Duration
+
+	// sendRate spaces out the messages sent to a chat, nil if disabled.
+	sendRate *sendGovernor
+
+	// sendMeter warns about the send rate close to the limits, nil if disabled.
+	sendMeter *sendMeter
+
+	// history keeps the last updates, nil if disabled.
+	history *updateHistory
+
+	// unhandled counts the updates no handler matched, nil if disabled.
+	unhandled *unhandledTypes
+
+	// urlUploadFallback uploads the media Telegram fails to fetch by URL.
+	urlUploadFallback bool
+
+	// correlateLogs adds the update fields to the context loggers.
+	correlateLogs bool
+
+	// foldCommands lowercases the incoming commands,
+	// see Settings.CaseInsensitiveCommands.
+	foldCommands bool
+
+	// sessionKey and sessionTTL configure the sessions,
+	// see Settings.SessionKey and Settings.SessionTTL.
+	sessionKey func(Context) string
+	sessionTTL time.Duration
+
+	// adminCacheTTL is the time the chat admins are cached for.
+	adminCacheTTL time.Duration
+
+	// defaultLocale is the last resort of Context.Locale.
+	defaultLocale string
+
+	// traceAPI logs the API calls, see LogConfig.TraceAPI.
+	traceAPI bool
+
+	// redactor keeps the secrets to remove from the log output,
+	// nil if disabled, see LogConfig.DisableRedaction.
+	redactor *redactor
+
+	// output is the logger of the framework messages, the bot logger
+	// with the secrets redacted.
+	output Logger
+
+	// logLevels are the level overrides of the named loggers,
+	// see LogConfig.Levels.
+	logLevels map[string]LogLevel
+
+	// dump logs the raw updates and requests, see LogConfig.DumpUpdates.
+	dump *dumper
+
+	// pollLogger samples the poller errors, which repeat on every
+	// attempt while Telegram is unavailable.
+	pollLogger *SampledLogger
+
+	// uploads is a semaphore limiting concurrent multipart uploads,
+	// nil means no limit.
+	uploads chan struct{}
+
+	*botState
+}
+
+// botState is the mutable state of Bot, which the bot bound to
+// an update context shares with the bot itself, see withContext.
+type botState struct {
+	// me is the bot user returned by Identity, which is shared with
+	// the copies of the bot bound to the handler contexts, so they
+	// see the refreshed identity as well.
+	me   *User
+	meMu sync.RWMutex
+	wg   sync.WaitGroup
+
+	// routesMu guards the handlers, so they can be added
+	// and removed while the bot is running, see Unhandle.
+	routesMu sync.RWMutex
+
+	albums   map[string]*albumBuffer
+	albumsMu sync.Mutex
+
+	// store keeps the bot state, see Store.
+	store   Store
+	storeMu sync.Mutex
+
+	// callbacks keeps the callback button states, see CallbackStore.
+	callbacks   CallbackStore
+	callbacksMu sync.Mutex
+
+	// sessions keeps the sessions, see Context.Session.
+	sessions   SessionStore
+	sessionsMu sync.Mutex
+
+	// business caches the business connections by their IDs.
+	business   map[string]*BusinessConnection
+	businessMu sync.Mutex
+
+	// timers are the pending timers, see afterFunc.
+	timers   map[*botTimer]struct{}
+	timersMu sync.Mutex
+
+	// warnedChats are the recipients already warned about, see warnChat.
+	warnedChats sync.Map
+}
+
+// withContext returns a copy of the bot, which makes
+// the requests with ctx, see Context.Ctx.
+func (b *Bot) withContext(ctx context.Context) *Bot {
+	bound := *b
+	bound.reqCtx = ctx
+	return &bound
+}
+
+// requestCtx returns the context of the requests, which is
+// cancelled once the bot is stopped.
+func (b *Bot) requestCtx() context.Context {
+	if b.reqCtx != nil {
+		return b.reqCtx
+	}
+	return b.rootCtx
 }
 
 // Settings represents a utility struct for passing certain
@@ -118,6 +337,9 @@ type Settings struct {
 
 	// Synchronous prevents handlers from running in parallel.
 	// It makes ProcessUpdate return after the handler is finished.
+	//
+	// Contexts are pooled and reused in synchronous mode, so don't
+	// keep a Context around once the handler has returned.
 	Synchronous bool
 
 	// Verbose forces bot to log all upcoming requests.
@@ -134,18 +356,122 @@ type Settings struct {
 	// Notice that context can be nil.
 	OnError func(error, Context)
 
+	// OnUnmatched is called with the updates no handler matched, OnAny
+	// included, so the unhandled traffic can be logged or measured.
+	OnUnmatched func(Context)
+
 	// HTTP Client used to make requests to telegram api
 	Client *http.Client
 
 	// Offline allows to create a bot without network for testing purposes.
 	Offline bool
 
-	// HandlerTimeout is the timeout for each handler.
+	// HandlerTimeout is the timeout for each handler. Once it passes,
+	// the context returned by Context.Ctx is cancelled.
 	HandlerTimeout time.Duration
 
 	// Log contains logging configuration.
 	// If nil, logging will be disabled.
 	Log *LogConfig
+
+	// MaxConcurrentUploads limits the number of multipart file uploads
+	// running at the same time, the rest are waiting in a queue.
+	// Zero means unlimited.
+	MaxConcurrentUploads int
+
+	// EditFallbackToSend makes Bot.Edit send the content as a new message
+	// when the edited one is too old to be edited (see ErrMessageTooOldToEdit),
+	// unless it's a markup or a live location.
+	EditFallbackToSend bool
+
+	// AlbumTimeout enables media groups aggregation. Messages of the same
+	// media group are buffered until no new ones arrive for the given
+	// duration, and then they are handled at once by the OnAlbum handler.
+	// Zero disables aggregation, as well as a missing OnAlbum handler.
+	AlbumTimeout time.Duration
+
+	// HandlerPriority overrides the order in which handlers are tried
+	// for a text message, DefaultHandlerPriority is used by default.
+	// Only the first matching handler runs, and the kinds missing from
+	// the list are never tried.
+	//
+	// The order within a kind is fixed as well: the command goes before
+	// the exact text endpoint, the longest prefix wins, the regexp handlers
+	// are tried in the order of registration, and OnReply runs along with
+	// OnText. Put RouteRegex first to let the regexps win over the commands
+	// they match.
+	HandlerPriority []RouteKind
+
+	// SendRate enables the send governor, which limits the rate of
+	// messages sent to each chat. If nil, messages are not limited.
+	SendRate *SendRateConfig
+
+	// RateWarningThreshold enables the send rate warnings, logged when
+	// the rate reaches this fraction of the Telegram limits, such as 0.8,
+	// overall or in a single chat. Zero disables them. The warnings only
+	// observe the rate, use SendRate to limit it.
+	RateWarningThreshold float64
+
+	// OnRateWarning is called with every send rate warning.
+	OnRateWarning func(RateWarning)
+
+	// Clock is the source of time for the time-dependent features,
+	// defaulted to the real clock. Replace it in tests to control time.
+	Clock Clock
+
+	// Store keeps the state of the stateful features, such as callback
+	// button states. Defaulted to the in-memory one, so the state is
+	// lost on restart. See Store for the contract.
+	Store Store
+
+	// SessionStore keeps the sessions, see Context.Session. Defaulted
+	// to the one backed by Store.
+	SessionStore SessionStore
+
+	// SessionKey returns the key of the session of the update,
+	// DefaultSessionKey if nil. The update with an empty key gets
+	// a session which isn't saved.
+	SessionKey func(Context) string
+
+	// SessionTTL is the time the sessions are kept for since the last
+	// change. Zero keeps them until they are cleared.
+	SessionTTL time.Duration
+
+	// AdminCacheTTL is the time the chat admins are cached for, see
+	// Bot.CachedAdminsOf. Defaulted to AdminCacheTTL.
+	AdminCacheTTL time.Duration
+
+	// DefaultLocale is the locale of the senders with no language
+	// known, see Context.Locale.
+	DefaultLocale string
+
+	// UpdateHistory is the number of the last processed updates kept
+	// for debugging, see Bot.RecentUpdates. Zero disables the history.
+	UpdateHistory int
+
+	// RedactUpdate is called on every update before it's put to the
+	// history, so the sensitive content can be removed. It's given a
+	// copy of the update, so it's safe to modify it in place.
+	RedactUpdate func(Update) Update
+
+	// TrackUnhandled enables counting the updates which matched no
+	// handler, see Bot.UnhandledTypes. Every newly seen type is logged.
+	TrackUnhandled bool
+
+	// URLUploadFallback makes the bot download the media sent by URL
+	// and upload it, if Telegram fails to fetch the URL itself.
+	URLUploadFallback bool
+
+	// CorrelateLogs makes Context.Logger attach a random correlation ID,
+	// the update ID, the chat ID and the sender ID to every message, so the
+	// lines logged for one update can be grouped. See LoggerWith.
+	CorrelateLogs bool
+
+	// CaseInsensitiveCommands makes "/Start" and "/START" reach the "/start"
+	// handler, since the mobile keyboards capitalize the first letter.
+	// The commands are lowercased before routing, so register them in
+	// lowercase, as Telegram requires anyway. The text is kept as is.
+	CaseInsensitiveCommands bool
 }
 
 var defaultOnError = func(err error, c Context) {
@@ -156,7 +482,15 @@ var defaultOnError = func(err error, c Context) {
 	}
 }
 
+// OnError reports the error to the error handler of the group which
+// handles the update, if any, or Settings.OnError otherwise.
 func (b *Bot) OnError(err error, c Context) {
+	if nc, ok := c.(*nativeContext); ok {
+		if onError := nc.errorHandler(); onError != nil {
+			onError(err, c)
+			return
+		}
+	}
 	b.onError(err, c)
 }
 
@@ -166,9 +500,15 @@ func (b *Bot) debug(err error) {
 	}
 }
 
-// Group returns a new group.
-func (b *Bot) Group() *Group {
-	return &Group{b: b}
+// Group returns a new group of handlers sharing the middleware.
+// The optional prefix is added to the commands, callback uniques
+// and callback patterns handled within the group, see Group.
+func (b *Bot) Group(prefix ...string) *Group {
+	g := &Group{b: b}
+	if len(prefix) > 0 {
+		g.prefix = prefix[0]
+	}
+	return g
 }
 
 // Use adds middleware to the global bot chain.
@@ -177,10 +517,47 @@ func (b *Bot) Use(middleware ...MiddlewareFunc) {
 }
 
 var (
-	cmdRx   = regexp.MustCompile(`^(/\w+)(@(\w+))?(\s|$)(.+)?`)
-	cbackRx = regexp.MustCompile(`^\f([-\w]+)(\|(.+))?$`)
+	cmdRx = regexp.MustCompile(`^(/\w+)(@(\w+))?(\s|$)(.+)?`)
 )
 
+// SplitCommand splits the command message text, "/command@bot payload",
+// into the command, the username of the bot it's addressed to, if any,
+// and the payload, the way the bot routes it. Reports whether the text
+// is a command.
+func SplitCommand(text string) (command, bot, payload string, ok bool) {
+	match := cmdRx.FindStringSubmatch(text)
+	if match == nil {
+		return "", "", "", false
+	}
+	return match[1], match[3], match[5], true
+}
+
+// Identity returns the bot user, safe to call from any goroutine.
+// It's an empty stub for an offline bot.
+func (b *Bot) Identity() *User {
+	b.meMu.RLock()
+	defer b.meMu.RUnlock()
+	return b.me
+}
+
+// RefreshIdentity fetches the bot user with getMe and replaces Me,
+// for example after the bot name is changed.
+func (b *Bot) RefreshIdentity() (*User, error) {
+	user, err := b.getMe()
+	if err != nil {
+		return nil, err
+	}
+	b.setIdentity(user)
+	return user, nil
+}
+
+func (b *Bot) setIdentity(user *User) {
+	b.meMu.Lock()
+	defer b.meMu.Unlock()
+	b.me = user
+	b.Me = user
+}
+
 // Handle lets you set the handler for some command name or
 // one of the supported endpoints. It also applies middleware
 // if such passed to the function.
@@ -195,20 +572,95 @@ var (
 //		return c.Respond(&tele.CallbackResponse{Text: "Hello!"})
 //	})
 //
+// An inline button handler also catches the family of buttons whose
+// unique starts with its unique and '_' or '-', such as "page_next"
+// for "page", unless they have their own handlers. For them, the
+// callback data is the full data, "page_next|payload".
+//
+// A slice of strings registers the handler for every endpoint
+// in it, so the aliases of a command share one handler:
+//
+//	b.Handle([]string{"/start", "/help", "/menu"}, onMenu)
+//
 // Middleware usage:
 //
 //	b.Handle("/ban", onBan, middleware.Whitelist(ids...))
+//
+// Handle is safe to call while the bot is running, see Unhandle.
 func (b *Bot) Handle(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	if aliases, ok := endpoint.([]string); ok && len(aliases) > 0 {
+		for _, alias := range aliases {
+			b.Handle(alias, h, m...)
+		}
+		return
+	}
+
 	end := extractEndpoint(endpoint)
 	if end == "" {
 		panic("telebot: unsupported endpoint")
 	}
 
+	handler := b.wrapHandler("handler:"+strings.TrimLeft(end, "\a\f"), h, m)
+
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+	b.handlers[end] = handler
+}
+
+// HandlePrefix adds the handler of the text messages starting with the
+// prefix, with the priority of RoutePrefix, see Settings.HandlerPriority.
+// The longest matching prefix wins:
+//
+//	b.HandlePrefix("order ", onOrder)
+//	b.HandlePrefix("order #", onOrderByID)
+func (b *Bot) HandlePrefix(prefix string, h HandlerFunc, m ...MiddlewareFunc) {
+	if prefix == "" {
+		panic("telebot: empty prefix")
+	}
+	route := prefixRoute{
+		prefix:  prefix,
+		handler: b.wrapHandler("handler:prefix:"+prefix, h, m),
+	}
+
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+
+	// The routes are replaced rather than changed in place, see removeRoutes.
+	i := sort.Search(len(b.prefixes), func(i int) bool {
+		return len(b.prefixes[i].prefix) < len(prefix)
+	})
+	prefixes := make([]prefixRoute, 0, len(b.prefixes)+1)
+	prefixes = append(prefixes, b.prefixes[:i]...)
+	prefixes = append(prefixes, route)
+	b.prefixes = append(prefixes, b.prefixes[i:]...)
+}
+
+// HandleRegexp adds the handler of the text messages matching the pattern.
+// The regexp handlers are tried in the order of registration, with the
+// priority of RouteRegex, see Settings.HandlerPriority. The submatches
+// are available with Context.Matches:
+//
+//	b.HandleRegexp(regexp.MustCompile(`^order #(\d+)$`), func(c tele.Context) error {
+//		return showOrder(c, c.Matches()[1])
+//	})
+func (b *Bot) HandleRegexp(pattern *regexp.Regexp, h HandlerFunc, m ...MiddlewareFunc) {
+	handler := b.wrapHandler("handler:regexp:"+pattern.String(), h, m)
+
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+	b.regexps = append(b.regexps, regexpRoute{pattern: pattern, handler: handler})
+}
+
+// wrapHandler applies the global and given middleware
+// to the handler, and names its logger.
+func (b *Bot) wrapHandler(name string, h HandlerFunc, m []MiddlewareFunc) HandlerFunc {
 	if len(b.group.middleware) > 0 {
 		m = appendMiddleware(b.group.middleware, m)
 	}
-
-	b.handlers[end] = func(c Context) error {
+	return func(c Context) error {
+		if nc, ok := c.(*nativeContext); ok {
+			nc.setLogName(name)
+		}
 		return applyMiddleware(h, m...)(c)
 	}
 }
@@ -220,7 +672,7 @@ func (b *Bot) Trigger(endpoint any, c Context) error {
 		return fmt.Errorf("telebot: unsupported endpoint")
 	}
 
-	handler, ok := b.handlers[end]
+	handler, ok := b.lookup(end, c)
 	if !ok {
 		return fmt.Errorf("telebot: no handler found for given endpoint")
 	}
@@ -234,6 +686,7 @@ func (b *Bot) Start() {
 	if b.Poller == nil {
 		panic("telebot: can't start without a poller")
 	}
+	b.checkBusinessSetup()
 
 	// Check if context is cancelled, create new one if needed
 	select {
@@ -271,11 +724,13 @@ func (b *Bot) Start() {
 	}
 }
 
-// Stop gracefully shuts the poller down.
+// Stop gracefully shuts the poller down. The pending deletions
+// scheduled with DeleteAfter are canceled.
 func (b *Bot) Stop() {
 	if b.cancel != nil {
 		b.cancel()
 	}
+	b.stopTimers()
 	// Wait for Start() to complete gracefully
 	b.wg.Wait()
 }
@@ -294,6 +749,10 @@ func (b *Bot) NewContext(u Update) Context {
 // Send accepts 2+ arguments, starting with destination chat, followed by
 // some Sendable (or string!) and optional send options.
 //
+// A plain File is sent as a photo, video, audio, animation or document,
+// depending on its sniffed content or extension. Pass a MediaType
+// option to choose the method explicitly.
+//
 // NOTE:
 //
 //	Since most arguments are of type any, but have pointer
@@ -305,16 +764,38 @@ func (b *Bot) NewContext(u Update) Context {
 //   - *ReplyMarkup (a component of SendOptions)
 //   - Option (a shortcut flag for popular options)
 //   - ParseMode (HTML, Markdown, etc)
+//   - MediaType (for a plain File)
 func (b *Bot) Send(to Recipient, what any, opts ...any) (*Message, error) {
 	if to == nil {
 		return nil, ErrBadRecipient
 	}
 
 	sendOpts := b.extractOptions(opts)
+	if err := sendOpts.validate(to, b.clock.Now()); err != nil {
+		return nil, err
+	}
+	if chat, ok := to.(*Chat); ok && chat.ID > 0 && chat.Type != "" && chat.Type != ChatPrivate {
+		b.warnChat(chat.Recipient(), "Chat %d of type %s has a positive ID, sending to %s instead", chat.ID, chat.Type, chat.Recipient())
+	}
+
+	msg, err := b.send(to, what, sendOpts)
+	if id, ok := to.(ChatID); ok && id > 0 && errors.Is(err, ErrChatNotFound) {
+		b.warnChat(id.Recipient(), "Chat %d is not found, the groups have negative IDs, see NormalizeChatID", id)
+	}
+	return msg, err
+}
 
+func (b *Bot) send(to Recipient, what any, sendOpts *SendOptions) (*Message, error) {
 	switch object := what.(type) {
 	case string:
 		return b.sendText(to, object, sendOpts)
+	case File:
+		return b.sendFile(to, object, sendOpts)
+	case *File:
+		if object == nil {
+			return nil, ErrUnsupportedWhat
+		}
+		return b.sendFile(to, *object, sendOpts)
 	case Sendable:
 		return object.Send(b, to, sendOpts)
 	default:
@@ -322,6 +803,86 @@ func (b *Bot) Send(to Recipient, what any, opts ...any) (*Message, error) {
 	}
 }
 
+// warnChat logs the warning about the recipient, once per recipient.
+func (b *Bot) warnChat(to string, format string, args ...any) {
+	if _, warned := b.warnedChats.LoadOrStore(to, struct{}{}); !warned {
+		b.output.Warn(format, args...)
+	}
+}
+
+// maxFloodRetries limits the retries of a message rate limited
+// by Telegram, see SendSequence.
+const maxFloodRetries = 3
+
+// SendSequence sends the items to the recipient as separate text
+// messages, in order. Pass a time.Duration along with the send options
+// to wait between the messages. The messages are spaced by the send
+// governor if it's enabled, and a flood error is waited out and the
+// message is retried.
+//
+// It stops on the first other error, returning the messages sent so far.
+func (b *Bot) SendSequence(to Recipient, items []string, opts ...any) ([]Message, error) {
+	if to == nil {
+		return nil, ErrBadRecipient
+	}
+
+	var delay time.Duration
+	sendOpts := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		if d, ok := opt.(time.Duration); ok {
+			delay = d
+		} else {
+			sendOpts = append(sendOpts, opt)
+		}
+	}
+
+	msgs := make([]Message, 0, len(items))
+	for i, item := range items {
+		if i > 0 && delay > 0 {
+			if err := b.sleep(delay); err != nil {
+				return msgs, err
+			}
+		}
+
+		msg, err := b.sendRetrying(to, item, sendOpts)
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, *msg)
+	}
+	return msgs, nil
+}
+
+func (b *Bot) sendRetrying(to Recipient, what any, opts []any) (*Message, error) {
+	for attempt := 0; ; attempt++ {
+		msg, err := b.Send(to, what, opts...)
+
+		var floodErr FloodError
+		if !errors.As(err, &floodErr) || attempt == maxFloodRetries {
+			return msg, err
+		}
+
+		retryAfter := time.Duration(floodErr.RetryAfter) * time.Second
+		b.output.Warn("Sending to %s is rate limited, retrying after %v", to.Recipient(), retryAfter)
+		if err := b.sleep(retryAfter); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for the duration, unless the bot is stopped.
+func (b *Bot) sleep(d time.Duration) error {
+	timer := b.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-b.requestCtx().Done():
+		return wrapError(b.requestCtx().Err())
+	}
+}
+
 // SendPaid sends multiple instances of paid media as a single message.
 // To include the caption, make sure the first PaidInputtable of an album has it.
 func (b *Bot) SendPaid(to Recipient, stars int, a PaidAlbum, opts ...any) (*Message, error) {
@@ -359,7 +920,9 @@ func (b *Bot) SendPaid(to Recipient, stars int, a PaidAlbum, opts ...any) (*Mess
 	}
 
 	params["media"] = "[" + strings.Join(media, ",") + "]"
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.sendFiles("sendPaidMedia", files, params)
 	if err != nil {
@@ -372,10 +935,17 @@ func (b *Bot) SendPaid(to Recipient, stars int, a PaidAlbum, opts ...any) (*Mess
 // SendAlbum sends multiple instances of media as a single message.
 // To include the caption, make sure the first Inputtable of an album has it.
 // From all existing options, it only supports tele.Silent.
+//
+// The sent messages are returned in the order of the album items,
+// and the file IDs of the items are set from them, so the files
+// can be reused without uploading them again.
 func (b *Bot) SendAlbum(to Recipient, a Album, opts ...any) ([]Message, error) {
 	if to == nil {
 		return nil, ErrBadRecipient
 	}
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
 
 	sendOpts := b.extractOptions(opts)
 	media := make([]string, len(a))
@@ -395,16 +965,30 @@ func (b *Bot) SendAlbum(to Recipient, a Album, opts ...any) ([]Message, error) {
 		} else {
 			im.ParseMode = sendOpts.ParseMode
 		}
+		if sendOpts.CaptionAbove && supportsCaptionAbove(im.Type) {
+			im.CaptionAbove = true
+		}
+		if sendOpts.HasSpoiler && im.Type != "audio" && im.Type != "document" {
+			im.HasSpoiler = true
+		}
 
 		data, _ := json.Marshal(im)
 		media[i] = string(data)
 	}
 
+	// The group level options, such as disable_notification and
+	// protect_content, apply to the whole album.
 	params := map[string]string{
 		"chat_id": to.Recipient(),
 		"media":   "[" + strings.Join(media, ",") + "]",
 	}
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
+
+	// The album items carry these flags themselves
+	delete(params, "show_caption_above_media")
+	delete(params, "has_spoiler")
 
 	data, err := b.sendFiles("sendMediaGroup", files, params)
 	if err != nil {
@@ -418,23 +1002,22 @@ func (b *Bot) SendAlbum(to Recipient, a Album, opts ...any) ([]Message, error) {
 		return nil, wrapError(err)
 	}
 
-	for attachName := range files {
-		i, _ := strconv.Atoi(attachName)
-		r := resp.Result[i]
-
-		var newID string
-		switch {
-		case r.Photo != nil:
-			newID = r.Photo.FileID
-		case r.Video != nil:
-			newID = r.Video.FileID
-		case r.Audio != nil:
-			newID = r.Audio.FileID
-		case r.Document != nil:
-			newID = r.Document.FileID
-		}
+	if len(resp.Result) != len(a) {
+		return resp.Result, fmt.Errorf("telebot: sent %d album messages out of %d", len(resp.Result), len(a))
+	}
 
-		a[i].MediaFile().FileID = newID
+	// The album messages are sent in a row,
+	// so their IDs follow the order of the items.
+	sort.SliceStable(resp.Result, func(i, j int) bool {
+		return resp.Result[i].ID < resp.Result[j].ID
+	})
+
+	for i, m := range resp.Result {
+		if media := m.Media(); media != nil {
+			f := a[i].MediaFile()
+			f.FileID = media.MediaFile().FileID
+			f.UniqueID = media.MediaFile().UniqueID
+		}
 	}
 
 	return resp.Result, nil
@@ -467,7 +1050,12 @@ func (b *Bot) Forward(to Recipient, msg Editable, opts ...any) (*Message, error)
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := sendOpts.validate(to, b.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("forwardMessage", params)
 	if err != nil {
@@ -504,7 +1092,15 @@ func (b *Bot) Copy(to Recipient, msg Editable, opts ...any) (*Message, error) {
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := sendOpts.validate(to, b.clock.Now()); err != nil {
+		return nil, err
+	}
+	if sendOpts.Caption != "" {
+		params["caption"] = sendOpts.Caption
+	}
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("copyMessage", params)
 	if err != nil {
@@ -528,6 +1124,68 @@ func (b *Bot) CopyMany(to Recipient, msgs []Editable, opts ...*SendOptions) ([]M
 	return b.forwardCopyMany(to, msgs, "copyMessages", opts...)
 }
 
+// RelayResult is the result of relaying messages to a single chat.
+type RelayResult struct {
+	To Recipient
+
+	// Messages hold the IDs of the copied messages.
+	Messages []Message
+
+	// Err is the error occurred while copying to the chat.
+	Err error
+}
+
+// RelayMany copies the messages with the given IDs from one chat to
+// many chats, without the link to the source. Set RemoveCaption in
+// the options to drop the captions of the copies.
+//
+// The copies are sent chat by chat, waiting for the send governor
+// if it's enabled, and a failure doesn't stop the relay. Check Err of
+// each result to find out which chats received the messages.
+func (b *Bot) RelayMany(to []Recipient, from Recipient, ids []int, opts ...*SendOptions) ([]RelayResult, error) {
+	if from == nil {
+		return nil, ErrBadRecipient
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("telebot: no messages to relay")
+	}
+
+	// Telegram requires the identifiers in strictly increasing order
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	results := make([]RelayResult, 0, len(to))
+	for _, chat := range to {
+		res := RelayResult{To: chat}
+		if chat == nil {
+			res.Err = ErrBadRecipient
+			results = append(results, res)
+			continue
+		}
+
+		params := map[string]string{
+			"chat_id":      chat.Recipient(),
+			"from_chat_id": from.Recipient(),
+			"message_ids":  string(data),
+		}
+		if len(opts) > 0 {
+			if err := b.embedSendOptions(params, opts[0]); err != nil {
+				res.Err = err
+				results = append(results, res)
+				continue
+			}
+		}
+
+		res.Messages, res.Err = b.sendMany("copyMessages", params)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
 // Edit is magic, it lets you change already sent message.
 // This function will panic upon nil Editable.
 //
@@ -538,12 +1196,38 @@ func (b *Bot) CopyMany(to Recipient, msgs []Editable, opts ...*SendOptions) ([]M
 //
 //	b.Edit(m, m.Text, newMarkup)
 //	b.Edit(m, "new <b>text</b>", tele.ModeHTML)
+//	b.Edit(m, text, entities) // instead of the parse mode
 //	b.Edit(m, &tele.ReplyMarkup{...})
 //	b.Edit(m, &tele.Photo{File: ...})
 //	b.Edit(m, tele.Location{42.1337, 69.4242})
 //	b.Edit(c, "edit inline message from the callback")
 //	b.Edit(r, "edit message from chosen inline result")
+//
+// If the message is too old to be edited, ErrMessageTooOldToEdit is returned.
+// With Settings.EditFallbackToSend enabled, the content is sent to the chat
+// as a new message instead, and the new message is returned. The markup and
+// the live location can't be sent on their own, so they don't fall back.
 func (b *Bot) Edit(msg Editable, what any, opts ...any) (*Message, error) {
+	m, err := b.edit(msg, what, opts...)
+	if err == ErrMessageTooOldToEdit && b.editFallback && sendableEdit(what) {
+		if _, chatID := msg.MessageSig(); chatID != 0 {
+			return b.Send(ChatID(chatID), what, opts...)
+		}
+	}
+	return m, err
+}
+
+// sendableEdit tells whether the edited content can be sent as a new message.
+func sendableEdit(what any) bool {
+	switch what.(type) {
+	case *ReplyMarkup, Location:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *Bot) edit(msg Editable, what any, opts ...any) (*Message, error) {
 	var (
 		method string
 		params = make(map[string]string)
@@ -588,11 +1272,23 @@ func (b *Bot) Edit(msg Editable, what any, opts ...any) (*Message, error) {
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if sendOpts.ReplyMarkup != nil {
+		if err := sendOpts.ReplyMarkup.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if text, ok := what.(string); ok {
+		if err := validateEntities("message", text, sendOpts.Entities); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw(method, params)
 	if err != nil {
-		return nil, err
+		return nil, b.editError(msg, err)
 	}
 
 	return extractMessage(data)
@@ -619,14 +1315,19 @@ func (b *Bot) EditReplyMarkup(msg Editable, markup *ReplyMarkup) (*Message, erro
 		// will delete reply markup
 		markup = &ReplyMarkup{}
 	}
+	if err := markup.validate(); err != nil {
+		return nil, err
+	}
 
-	processButtons(markup.InlineKeyboard)
+	if err := b.processButtons(markup.InlineKeyboard); err != nil {
+		return nil, err
+	}
 	data, _ := json.Marshal(markup)
 	params["reply_markup"] = string(data)
 
 	data, err := b.Raw("editMessageReplyMarkup", params)
 	if err != nil {
-		return nil, err
+		return nil, b.editError(msg, err)
 	}
 
 	return extractMessage(data)
@@ -652,11 +1353,13 @@ func (b *Bot) EditCaption(msg Editable, caption string, opts ...any) (*Message,
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("editMessageCaption", params)
 	if err != nil {
-		return nil, err
+		return nil, b.editError(msg, err)
 	}
 
 	return extractMessage(data)
@@ -715,12 +1418,29 @@ func (b *Bot) EditMedia(msg Editable, media Inputtable, opts ...any) (*Message,
 	msgID, chatID := msg.MessageSig()
 	params := make(map[string]string)
 
+	// Telegram doesn't accept new uploads for inline messages.
+	if chatID == 0 && len(files) > 0 {
+		return nil, fmt.Errorf("telebot: cannot upload a new file to an inline message, use file ID or URL")
+	}
+
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	im := media.InputMedia()
 	im.Media = repr
 
+	// The input media carries the flag itself
+	if _, ok := params["show_caption_above_media"]; ok {
+		delete(params, "show_caption_above_media")
+		if supportsCaptionAbove(im.Type) {
+			im.CaptionAbove = true
+		} else {
+			b.output.Debug("Caption above media is not supported for %s, ignored", im.Type)
+		}
+	}
+
 	if len(sendOpts.Entities) > 0 {
 		im.Entities = sendOpts.Entities
 	} else {
@@ -744,7 +1464,7 @@ func (b *Bot) EditMedia(msg Editable, media Inputtable, opts ...any) (*Message,
 
 	data, err := b.sendFiles("editMessageMedia", files, params)
 	if err != nil {
-		return nil, err
+		return nil, b.editError(msg, err)
 	}
 
 	return extractMessage(data)
@@ -864,10 +1584,18 @@ func (b *Bot) Accept(query *PreCheckoutQuery, errorMessage ...string) error {
 	return err
 }
 
+// maxCallbackRetryAfter is the longest flood wait worth retrying
+// the callback answer, the query expires soon after.
+const maxCallbackRetryAfter = 5
+
 // Respond sends a response for a given callback query. A callback can
 // only be responded to once, subsequent attempts to respond to the same callback
 // will result in an error.
 //
+// A rate limited answer is retried once if the wait is short enough.
+// If the query is too old to be answered, the error wraps both
+// ErrCallbackExpired and ErrQueryTooOld.
+//
 // Example:
 //
 //	b.Respond(c)
@@ -882,6 +1610,19 @@ func (b *Bot) Respond(c *Callback, resp ...*CallbackResponse) error {
 
 	r.CallbackID = c.ID
 	_, err := b.Raw("answerCallbackQuery", r)
+
+	var floodErr FloodError
+	if errors.As(err, &floodErr) && floodErr.RetryAfter <= maxCallbackRetryAfter {
+		b.output.Warn("Callback answer is rate limited, retrying after %ds", floodErr.RetryAfter)
+		if err := b.sleep(time.Duration(floodErr.RetryAfter) * time.Second); err != nil {
+			return err
+		}
+		_, err = b.Raw("answerCallbackQuery", r)
+	}
+
+	if errors.Is(err, ErrQueryTooOld) {
+		return fmt.Errorf("%w: %w", ErrCallbackExpired, err)
+	}
 	return err
 }
 
@@ -892,6 +1633,9 @@ func (b *Bot) Answer(query *Query, resp *QueryResponse) error {
 	resp.QueryID = query.ID
 
 	for _, result := range resp.Results {
+		if err := b.storeResultStates(result); err != nil {
+			return err
+		}
 		result.Process(b)
 	}
 
@@ -902,6 +1646,9 @@ func (b *Bot) Answer(query *Query, resp *QueryResponse) error {
 // AnswerWebApp sends a response for a query from Web App and returns
 // information about an inline message sent by a Web App on behalf of a user
 func (b *Bot) AnswerWebApp(query *Query, r Result) (*WebAppMessage, error) {
+	if err := b.storeResultStates(r); err != nil {
+		return nil, err
+	}
 	r.Process(b)
 
 	params := map[string]any{
@@ -972,6 +1719,46 @@ func (b *Bot) Download(file *File, localFilename string) error {
 	return nil
 }
 
+// DownloadProgress is called by DownloadTo as the file is being
+// downloaded with the number of bytes written so far and the file size,
+// which is zero if unknown.
+type DownloadProgress func(written, total int64)
+
+// DownloadTo streams the file from Telegram servers to w without
+// buffering it in memory. The progress callbacks are optional.
+// Maximum file size to download is 20 MB.
+func (b *Bot) DownloadTo(file *File, w io.Writer, progress ...DownloadProgress) error {
+	reader, err := b.File(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if len(progress) > 0 {
+		w = &progressWriter{w: w, total: file.FileSize, progress: progress}
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress []DownloadProgress
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.written += int64(n)
+	for _, f := range pw.progress {
+		f(pw.written, pw.total)
+	}
+	return n, err
+}
+
 // File gets a file from Telegram servers.
 func (b *Bot) File(file *File) (io.ReadCloser, error) {
 	f, err := b.FileByID(file.FileID)
@@ -981,8 +1768,11 @@ func (b *Bot) File(file *File) (io.ReadCloser, error) {
 
 	url := b.URL + "/file/bot" + b.Token + "/" + f.FilePath
 	file.FilePath = f.FilePath // saving file path
+	if file.FileSize == 0 {
+		file.FileSize = f.FileSize
+	}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(b.requestCtx(), http.MethodGet, url, nil)
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -1010,14 +1800,19 @@ func (b *Bot) File(file *File) (io.ReadCloser, error) {
 // otherwise returns nil and ErrTrueResult.
 func (b *Bot) StopLiveLocation(msg Editable, opts ...any) (*Message, error) {
 	msgID, chatID := msg.MessageSig()
+	params := make(map[string]string)
 
-	params := map[string]string{
-		"chat_id":    strconv.FormatInt(chatID, 10),
-		"message_id": msgID,
+	if chatID == 0 { // if inline message
+		params["inline_message_id"] = msgID
+	} else {
+		params["chat_id"] = strconv.FormatInt(chatID, 10)
+		params["message_id"] = msgID
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("stopMessageLiveLocation", params)
 	if err != nil {
@@ -1041,7 +1836,9 @@ func (b *Bot) StopPoll(msg Editable, opts ...any) (*Poll, error) {
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("stopPoll", params)
 	if err != nil {
@@ -1080,7 +1877,9 @@ func (b *Bot) Pin(msg Editable, opts ...any) error {
 	}
 
 	sendOpts := b.extractOptions(opts)
-	b.embedSendOptions(params, sendOpts)
+	if err := b.embedSendOptions(params, sendOpts); err != nil {
+		return err
+	}
 
 	_, err := b.Raw("pinChatMessage", params)
 	return err
@@ -1365,6 +2164,30 @@ func (b *Bot) botInfo(language, key string) (*BotInfo, error) {
 	return resp.Result, nil
 }
 
+// editWindow is the period of time during which bots can edit messages.
+const editWindow = 48 * time.Hour
+
+// editError turns ErrCantEditMessage into ErrMessageTooOldToEdit
+// when the edited message is known to be out of the editing window.
+func (b *Bot) editError(msg Editable, err error) error {
+	if err != ErrCantEditMessage {
+		return err
+	}
+
+	var m *Message
+	switch v := msg.(type) {
+	case *Message:
+		m = v
+	case *Callback:
+		m = v.Message
+	}
+
+	if m != nil && m.Unixtime != 0 && b.clock.Now().Sub(m.Time()) > editWindow {
+		return ErrMessageTooOldToEdit
+	}
+	return err
+}
+
 func extractEndpoint(endpoint any) string {
 	switch end := endpoint.(type) {
 	case string:
@@ -2,13 +2,18 @@ package telebot
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,16 +22,30 @@ import (
 // Raw lets you call any method of Bot API manually.
 // It also handles API errors, so you only need to unwrap
 // result field from json data.
-func (b *Bot) Raw(method string, payload any) ([]byte, error) {
+func (b *Bot) Raw(method string, payload any) (data []byte, err error) {
 	url := b.URL + "/bot" + b.Token + "/" + method
 
+	var status int
+	if b.tracing() {
+		start := time.Now()
+		defer func() {
+			b.trace(method, payload, nil, time.Since(start), status, data, err)
+		}()
+	}
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
 		return nil, err
 	}
+	b.dump.request(method, buf.Bytes())
+
+	report, err := b.throttle(method, payload)
+	if err != nil {
+		return nil, err
+	}
 
 	// Use bot's context for automatic cancellation when bot stops
-	req, err := http.NewRequestWithContext(b.rootCtx, http.MethodPost, url, &buf)
+	req, err := http.NewRequestWithContext(b.requestCtx(), http.MethodPost, url, &buf)
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -38,21 +57,24 @@ func (b *Bot) Raw(method string, payload any) ([]byte, error) {
 	}
 	resp.Close = true
 	defer resp.Body.Close()
+	status = resp.StatusCode
 
-	data, err := io.ReadAll(resp.Body)
+	data, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, wrapError(err)
 	}
 
 	if b.verbose {
-		verbose(method, payload, data)
+		verbose(method, payload, data, b.redactor.redact)
 	}
 
 	// returning data as well
-	return data, extractOk(data)
+	err = extractOk(data)
+	report(err)
+	return data, err
 }
 
-func (b *Bot) sendFiles(method string, files map[string]File, params map[string]string) ([]byte, error) {
+func (b *Bot) sendFiles(method string, files map[string]File, params map[string]string) (data []byte, err error) {
 	rawFiles := make(map[string]any)
 	for name, f := range files {
 		switch {
@@ -72,11 +94,57 @@ func (b *Bot) sendFiles(method string, files map[string]File, params map[string]
 	if len(rawFiles) == 0 {
 		return b.Raw(method, params)
 	}
+	if b.dump.enabled() {
+		body, _ := json.Marshal(params)
+		b.dump.request(method, body)
+	}
+
+	var status int
+	if b.tracing() {
+		start := time.Now()
+		defer func() {
+			b.trace(method, params, rawFiles, time.Since(start), status, data, err)
+		}()
+	}
+
+	// The readers are left to the caller after a successful upload,
+	// but there is no use of them after a failed one.
+	readersClosed := false
+	defer func() {
+		if err != nil && !readersClosed {
+			closeReaders(rawFiles)
+		}
+	}()
+
+	report, err := b.throttle(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.acquireUpload(); err != nil {
+		return nil, err
+	}
+	defer b.releaseUpload()
 
 	pipeReader, pipeWriter := io.Pipe()
 	writer := multipart.NewWriter(pipeWriter)
+	written := make(chan struct{})
+
+	// Closing the pipe interrupts the writing goroutine if the request
+	// has failed midway, and closing the readers interrupts it if it's
+	// blocked reading one of them. Waiting for it guarantees all the
+	// opened files are closed by the time sendFiles returns.
+	defer func() {
+		pipeReader.Close()
+		if err != nil {
+			closeReaders(rawFiles)
+			readersClosed = true
+		}
+		<-written
+	}()
 
 	go func() {
+		defer close(written)
 		defer pipeWriter.Close()
 
 		for field, file := range rawFiles {
@@ -97,27 +165,71 @@ func (b *Bot) sendFiles(method string, files map[string]File, params map[string]
 		}
 	}()
 
+	// The client waits for the body to be written even after the request
+	// is cancelled, so the pipe is closed right away to let it return.
+	ctx := b.requestCtx()
+	stop := context.AfterFunc(ctx, func() { pipeWriter.CloseWithError(ctx.Err()) })
+	defer stop()
+
 	url := b.URL + "/bot" + b.Token + "/" + method
 
-	resp, err := b.client.Post(url, writer.FormDataContentType(), pipeReader)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pipeReader)
 	if err != nil {
-		err = wrapError(err)
-		pipeReader.CloseWithError(err)
-		return nil, err
+		return nil, wrapError(err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, wrapError(err)
 	}
 	resp.Close = true
 	defer resp.Body.Close()
+	status = resp.StatusCode
 
 	if resp.StatusCode == http.StatusInternalServerError {
 		return nil, ErrInternal
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	data, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, wrapError(err)
 	}
 
-	return data, extractOk(data)
+	err = extractOk(data)
+	report(err)
+	return data, err
+}
+
+// closeReaders closes the upload readers implementing io.Closer.
+func closeReaders(rawFiles map[string]any) {
+	for _, file := range rawFiles {
+		if c, ok := file.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// acquireUpload takes an upload slot, waiting for one if the
+// MaxConcurrentUploads limit is reached. Waiting is interrupted
+// once the bot is stopped.
+func (b *Bot) acquireUpload() error {
+	if b.uploads == nil {
+		return nil
+	}
+
+	select {
+	case b.uploads <- struct{}{}:
+		return nil
+	case <-b.requestCtx().Done():
+		return wrapError(b.requestCtx().Err())
+	}
+}
+
+func (b *Bot) releaseUpload() {
+	if b.uploads != nil {
+		<-b.uploads
+	}
 }
 
 func addFileToWriter(writer *multipart.Writer, filename, field string, file any) error {
@@ -162,7 +274,10 @@ func (b *Bot) sendText(to Recipient, text string, opt *SendOptions) (*Message, e
 		"chat_id": to.Recipient(),
 		"text":    text,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
+	b.checkCaptionAbove("text", params)
 
 	data, err := b.Raw("sendMessage", params)
 	if err != nil {
@@ -172,9 +287,34 @@ func (b *Bot) sendText(to Recipient, text string, opt *SendOptions) (*Message, e
 	return extractMessage(data)
 }
 
+func (b *Bot) sendFile(to Recipient, f File, opt *SendOptions) (*Message, error) {
+	kind := opt.MediaType
+	if kind == "" {
+		kind = f.detectMediaType()
+	}
+
+	var what Sendable
+	switch kind {
+	case MediaPhoto:
+		what = &Photo{File: f}
+	case MediaVideo:
+		what = &Video{File: f}
+	case MediaAudio:
+		what = &Audio{File: f}
+	case MediaAnimation:
+		what = &Animation{File: f}
+	case MediaDocument:
+		what = &Document{File: f}
+	default:
+		return nil, fmt.Errorf("telebot: unsupported media type %q", kind)
+	}
+	return what.Send(b, to, opt)
+}
+
 func (b *Bot) sendMedia(media Media, params map[string]string, files map[string]File) (*Message, error) {
 	kind := media.MediaType()
 	what := "send" + strings.Title(kind)
+	b.checkCaptionAbove(kind, params)
 
 	if kind == "videoNote" {
 		kind = "video_note"
@@ -186,6 +326,10 @@ func (b *Bot) sendMedia(media Media, params map[string]string, files map[string]
 	}
 
 	data, err := b.sendFiles(what, sendFiles, params)
+	if f := sendFiles[kind]; err != nil && b.urlUploadFallback && f.FileURL != "" && !f.InCloud() &&
+		(errors.Is(err, ErrWrongFileID) || errors.Is(err, ErrBadURLContent)) {
+		data, err = b.reuploadURL(what, kind, sendFiles, params)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +337,61 @@ func (b *Bot) sendMedia(media Media, params map[string]string, files map[string]
 	return extractMessage(data)
 }
 
+// reuploadURL downloads the file Telegram failed to fetch by its URL
+// and uploads it instead, see Settings.URLUploadFallback.
+func (b *Bot) reuploadURL(method, field string, files map[string]File, params map[string]string) ([]byte, error) {
+	f := files[field]
+	b.output.Info("Telegram failed to fetch %s, uploading it instead", f.FileURL)
+
+	req, err := http.NewRequestWithContext(b.requestCtx(), http.MethodGet, f.FileURL, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("telebot: expected status 200 but got %s", resp.Status)
+	}
+
+	if f.fileName == "" {
+		if u, err := url.Parse(f.FileURL); err == nil {
+			f.fileName = path.Base(u.Path)
+		}
+	}
+	f.FileURL = ""
+	f.FileReader = resp.Body
+	files[field] = f
+
+	// The URL is sent as the field value by the first attempt
+	delete(params, field)
+	return b.sendFiles(method, files, params)
+}
+
+// supportsCaptionAbove reports whether the caption
+// can be shown above the media of the kind.
+func supportsCaptionAbove(kind string) bool {
+	switch kind {
+	case "photo", "video", "animation":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkCaptionAbove drops show_caption_above_media from the params
+// if the media kind doesn't support it.
+func (b *Bot) checkCaptionAbove(kind string, params map[string]string) {
+	if _, ok := params["show_caption_above_media"]; !ok || supportsCaptionAbove(kind) {
+		return
+	}
+	delete(params, "show_caption_above_media")
+	b.output.Debug("Caption above media is not supported for %s, ignored", kind)
+}
+
 func (b *Bot) getMe() (*User, error) {
 	data, err := b.Raw("getMe", nil)
 	if err != nil {
@@ -232,21 +431,40 @@ func (b *Bot) getUpdates(offset, limit int, timeout time.Duration, allowed []str
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, wrapError(err)
 	}
+
+	if b.dump.enabled() {
+		var raw struct {
+			Result []json.RawMessage
+		}
+		json.Unmarshal(data, &raw)
+		for i, u := range resp.Result {
+			if i < len(raw.Result) {
+				b.dump.update(u, raw.Result[i])
+			}
+		}
+	}
 	return resp.Result, nil
 }
 
 func (b *Bot) forwardCopyMany(to Recipient, msgs []Editable, key string, opts ...*SendOptions) ([]Message, error) {
-	params := map[string]string{
-		"chat_id": to.Recipient(),
-	}
-
+	params := make(map[string]string)
 	embedMessages(params, msgs)
 
+	// embedMessages sets the chat of the messages, which is the source
+	params["from_chat_id"] = params["chat_id"]
+	params["chat_id"] = to.Recipient()
+
 	if len(opts) > 0 {
-		b.embedSendOptions(params, opts[0])
+		if err := b.embedSendOptions(params, opts[0]); err != nil {
+			return nil, err
+		}
 	}
 
-	data, err := b.Raw(key, params)
+	return b.sendMany(key, params)
+}
+
+func (b *Bot) sendMany(method string, params map[string]string) ([]Message, error) {
+	data, err := b.Raw(method, params)
 	if err != nil {
 		return nil, err
 	}
@@ -339,7 +557,42 @@ func extractMessage(data []byte) (*Message, error) {
 	return resp.Result, nil
 }
 
-func verbose(method string, payload any, data []byte) {
+// tracing reports whether the API calls are logged, see LogConfig.TraceAPI.
+func (b *Bot) tracing() bool {
+	return b.traceAPI && b.logger.LogMode() <= LogLevelDebug
+}
+
+// trace logs the API call with the token redacted. The uploaded files
+// are listed by their fields only.
+func (b *Bot) trace(method string, payload any, files map[string]any, took time.Duration, status int, data []byte, err error) {
+	params, _ := json.Marshal(payload)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "telebot: API call %s: status %d in %v, params %s", method, status, took, params)
+
+	if len(files) > 0 {
+		fields := make([]string, 0, len(files))
+		for field := range files {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		fmt.Fprintf(&sb, ", files %v", fields)
+	}
+
+	if err != nil {
+		fmt.Fprintf(&sb, ", error: %v", err)
+	} else {
+		fmt.Fprintf(&sb, ", ok: %d bytes", len(data))
+	}
+
+	line := sb.String()
+	if b.Token != "" {
+		line = strings.ReplaceAll(line, b.Token, "<token>")
+	}
+	b.output.Debug("%s", line)
+}
+
+func verbose(method string, payload any, data []byte, redact func(string) string) {
 	body, _ := json.Marshal(payload)
 	body = bytes.ReplaceAll(body, []byte(`\"`), []byte(`"`))
 	body = bytes.ReplaceAll(body, []byte(`"{`), []byte(`{`))
@@ -351,8 +604,8 @@ func verbose(method string, payload any, data []byte) {
 		return buf.String()
 	}
 
-	log.Printf(
+	log.Print(redact(fmt.Sprintf(
 		"[verbose] telebot: sent request\nMethod: %v\nParams: %v\nResponse: %v",
 		method, indent(body), indent(data),
-	)
+	)))
 }
@@ -1,13 +1,17 @@
 package telebot
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -118,3 +122,212 @@ func TestExtractMessage(t *testing.T) {
 	_, err = extractMessage(data)
 	require.NoError(t, err)
 }
+
+func TestMaxConcurrentUploads(t *testing.T) {
+	var active, peak int32
+
+	srv := newTestAPI(t, func(call apiCall) string {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		return `{"ok":true,"result":true}`
+	})
+
+	b, err := NewBot(Settings{
+		URL:                  srv.URL,
+		Offline:              true,
+		MaxConcurrentUploads: 2,
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			files := map[string]File{"document": FromReader(strings.NewReader("data"))}
+			_, err := b.sendFiles("sendDocument", files, map[string]string{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	assert.Len(t, b.uploads, 0)
+
+	t.Run("cancel", func(t *testing.T) {
+		b.uploads <- struct{}{}
+		b.uploads <- struct{}{}
+		b.cancel()
+
+		files := map[string]File{"document": FromReader(strings.NewReader("data"))}
+		_, err := b.sendFiles("sendDocument", files, map[string]string{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// failingReader returns an error after the first read
+// and tracks whether it was closed.
+type failingReader struct {
+	read   bool
+	closed bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, errors.New("connection dropped")
+	}
+	r.read = true
+	return copy(p, "partial"), nil
+}
+
+func (r *failingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestSendFilesFailure(t *testing.T) {
+	srv := newTestAPI(t, func(call apiCall) string {
+		return `{"ok":true,"result":{"message_id":1,"document":{"file_id":"new"}}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	r := &failingReader{}
+	doc := &Document{File: FromReader(r)}
+
+	_, err = b.Send(&Chat{ID: 1}, doc)
+	assert.ErrorContains(t, err, "connection dropped")
+	assert.True(t, r.closed)
+	assert.Empty(t, doc.FileID)
+}
+
+// blockingReader blocks reading until it's closed.
+type blockingReader struct {
+	closed chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (r *blockingReader) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func TestSendFilesBlockingReader(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		files := map[string]File{"document": FromReader(&blockingReader{closed: make(chan struct{})})}
+		_, err := b.sendFiles("sendDocument", files, map[string]string{})
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	b.cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("sendFiles is blocked on the reader")
+	}
+}
+
+func TestTraceAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if strings.HasSuffix(r.URL.Path, "/getChat") {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1,"document":{"file_id":"new"}}}`))
+	}))
+	defer srv.Close()
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{
+		URL:     srv.URL,
+		Token:   "123:secret",
+		Offline: true,
+		Log:     &LogConfig{Enable: true, Logger: logger, TraceAPI: true},
+	})
+	require.NoError(t, err)
+
+	_, err = b.Send(&Chat{ID: 1}, "hi, 123:secret")
+	require.NoError(t, err)
+
+	_, err = b.Send(&Chat{ID: 1}, &Document{File: FromReader(strings.NewReader("data"))})
+	require.NoError(t, err)
+
+	_, err = b.ChatByID(2)
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(logger.GetOutput()), "\n")
+	require.Len(t, lines, 3)
+
+	assert.Regexp(t, `^\[CUSTOM\] \[DEBUG\] telebot: API call sendMessage: status 200 in \S+, params \{.*"text":"hi, \<token\>".*\}, ok: \d+ bytes$`, lines[0])
+	assert.Regexp(t, `API call sendDocument: status 200 in \S+, params .*, files \[document\], ok`, lines[1])
+	assert.Regexp(t, `API call getChat: status 400 in \S+, params \{"chat_id":"2"\}, error: .*chat not found`, lines[2])
+	assert.NotContains(t, logger.GetOutput(), "secret")
+}
+
+func TestDumpUpdates(t *testing.T) {
+	srv := newTestAPI(t, func(call apiCall) string {
+		if call.Method == "getUpdates" {
+			return `{"ok":true,"result":[` +
+				`{"update_id":1,"message":{"message_id":1,"chat":{"id":1},"new_field":true}},` +
+				`{"update_id":2,"message":{"message_id":2,"chat":{"id":2}}}]}`
+		}
+		return `{"ok":true,"result":{"message_id":1}}`
+	})
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{
+		URL:     srv.URL,
+		Offline: true,
+		Log: &LogConfig{
+			Enable:      true,
+			Logger:      logger,
+			DumpUpdates: true,
+			DumpFilter:  func(chat string) bool { return chat == "1" },
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = b.getUpdates(0, 0, 0, nil)
+	require.NoError(t, err)
+	_, err = b.Send(&Chat{ID: 1}, "hi")
+	require.NoError(t, err)
+	_, err = b.Send(&Chat{ID: 2}, "hidden")
+	require.NoError(t, err)
+
+	out := logger.GetOutput()
+	assert.Contains(t, out, "[DEBUG] [dump] Update 1:\n{\n  \"update_id\": 1,")
+	assert.Contains(t, out, `"new_field": true`)
+	assert.NotContains(t, out, "Update 2")
+	assert.Contains(t, out, "[DEBUG] [dump] Request sendMessage:\n{\n  \"chat_id\": \"1\",")
+	assert.NotContains(t, out, "hidden")
+}
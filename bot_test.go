@@ -1,12 +1,18 @@
 package telebot
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -39,6 +45,47 @@ func newTestBot() (*Bot, error) {
 	return NewBot(defaultSettings())
 }
 
+// apiCall is a request received by the fake Bot API, see newTestAPI.
+type apiCall struct {
+	Method string
+	Params map[string]string // the nested values are left as JSON
+	Body   []byte            // the raw JSON body, if any
+}
+
+// newTestAPI starts a fake Bot API, which answers each call with the
+// response returned by respond. It's closed once the test finishes.
+// The calls are handled on the server goroutine, so respond must not
+// use require, which is only allowed on the test goroutine.
+func newTestAPI(t *testing.T, respond func(call apiCall) string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := apiCall{Method: path.Base(r.URL.Path), Params: make(map[string]string)}
+
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			if assert.NoError(t, r.ParseMultipartForm(1<<20)) {
+				for k, v := range r.MultipartForm.Value {
+					call.Params[k] = v[0]
+				}
+			}
+		} else if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+			call.Body = body
+			var params map[string]json.RawMessage
+			if assert.NoError(t, json.Unmarshal(body, &params)) {
+				for k, v := range params {
+					var s string
+					if json.Unmarshal(v, &s) != nil {
+						s = string(v)
+					}
+					call.Params[k] = s
+				}
+			}
+		}
+
+		io.WriteString(w, respond(call))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
 func TestNewBot(t *testing.T) {
 	var pref Settings
 	_, err := NewBot(pref)
@@ -444,6 +491,46 @@ func TestBotMiddleware(t *testing.T) {
 		}, trace)
 	})
 
+	t.Run("abort", func(t *testing.T) {
+		var reported []error
+		b, err := NewBot(Settings{
+			Synchronous: true,
+			Offline:     true,
+			OnError:     func(err error, _ Context) { reported = append(reported, err) },
+		})
+		require.NoError(t, err)
+
+		var aborted bool
+		b.Use(func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				err := next(c)
+				aborted = c.Aborted()
+				return err
+			}
+		})
+
+		deny := func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				return c.Abort()
+			}
+		}
+		b.Handle("/a", func(c Context) error {
+			t.Fatal("aborted handler should not be called")
+			return nil
+		}, deny)
+		b.Handle("/b", func(c Context) error { return ErrSkip })
+		b.Handle("/c", func(c Context) error { return ErrNotFound })
+
+		b.ProcessUpdate(Update{Message: &Message{Text: "/a"}})
+		assert.True(t, aborted)
+		b.ProcessUpdate(Update{Message: &Message{Text: "/b"}})
+		assert.False(t, aborted)
+		assert.Empty(t, reported)
+
+		b.ProcessUpdate(Update{Message: &Message{Text: "/c"}})
+		assert.Equal(t, []error{ErrNotFound}, reported)
+	})
+
 	fatal := func(next HandlerFunc) HandlerFunc {
 		return func(c Context) error {
 			t.Fatal("fatal middleware should not be called")
@@ -493,6 +580,600 @@ func TestBotMiddleware(t *testing.T) {
 	})
 }
 
+func TestBotEditFallback(t *testing.T) {
+	srv := newTestAPI(t, func(call apiCall) string {
+		if call.Method == "sendMessage" {
+			return `{"ok":true,"result":{"message_id":2,"text":"new"}}`
+		}
+		return `{"ok":false,"error_code":400,"description":"Bad Request: message can't be edited"}`
+	})
+
+	old := &Message{ID: 1, Chat: &Chat{ID: 1}, Unixtime: time.Now().Add(-72 * time.Hour).Unix()}
+	recent := &Message{ID: 1, Chat: &Chat{ID: 1}, Unixtime: time.Now().Unix()}
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	_, err = b.Edit(old, "new")
+	assert.Equal(t, ErrMessageTooOldToEdit, err)
+	assert.ErrorIs(t, err, ErrCantEditMessage)
+
+	_, err = b.Edit(recent, "new")
+	assert.Equal(t, ErrCantEditMessage, err)
+	assert.NotErrorIs(t, err, ErrMessageTooOldToEdit)
+
+	_, err = b.Edit(&Callback{Message: old}, "new")
+	assert.Equal(t, ErrMessageTooOldToEdit, err)
+
+	b, err = NewBot(Settings{URL: srv.URL, Offline: true, EditFallbackToSend: true})
+	require.NoError(t, err)
+
+	msg, err := b.Edit(old, "new")
+	require.NoError(t, err)
+	assert.Equal(t, 2, msg.ID)
+
+	// The live location can't be sent on its own
+	_, err = b.Edit(old, Location{Lat: 1, Lng: 2})
+	assert.Equal(t, ErrMessageTooOldToEdit, err)
+}
+
+func TestBotSendAlbumOrder(t *testing.T) {
+	srv := newTestAPI(t, func(call apiCall) string {
+		// The messages are deliberately out of order
+		return `{"ok":true,"result":[
+			{"message_id":11,"chat":{"id":1},"video":{"file_id":"video","file_unique_id":"v"}},
+			{"message_id":10,"chat":{"id":1},"photo":[{"file_id":"small"},{"file_id":"photo","file_unique_id":"p"}]}
+		]}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	photo := &Photo{File: FromReader(strings.NewReader("photo"))}
+	video := &Video{File: FromURL("https://example.com/video.mp4")}
+
+	msgs, err := b.SendAlbum(&Chat{ID: 1}, Album{photo, video})
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, 10, msgs[0].ID)
+	assert.Equal(t, 11, msgs[1].ID)
+
+	assert.Equal(t, "photo", photo.FileID)
+	assert.Equal(t, "p", photo.UniqueID)
+	assert.Equal(t, "video", video.FileID)
+	assert.Equal(t, "v", video.UniqueID)
+}
+
+func TestBotSendAlbumOptions(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		return `{"ok":true,"result":[{"message_id":1,"chat":{"id":1}},{"message_id":2,"chat":{"id":1}}]}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	for _, photo := range []*Photo{
+		{File: FromURL("https://example.com/photo.jpg")},
+		{File: FromReader(strings.NewReader("photo"))},
+	} {
+		album := Album{photo, &Video{File: FromURL("https://example.com/video.mp4")}}
+		_, err = b.SendAlbum(&Chat{ID: 1}, album, &SendOptions{HasSpoiler: true}, Silent, Protected)
+		require.NoError(t, err)
+
+		assert.Equal(t, "true", params["disable_notification"])
+		assert.Equal(t, "true", params["protect_content"])
+		assert.NotContains(t, params, "has_spoiler")
+
+		var media []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(params["media"]), &media))
+		for _, im := range media {
+			assert.NotContains(t, im, "disable_notification")
+			assert.NotContains(t, im, "protect_content")
+			assert.Equal(t, true, im["has_spoiler"])
+		}
+	}
+}
+
+func TestBotSendCrossChatReply(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	chatA, chatB := &Chat{ID: 1}, int64(2)
+
+	_, err = b.Send(chatA, "text", &ReplyParams{ChatID: chatB, MessageID: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "1", params["chat_id"])
+	assert.JSONEq(t, `{"chat_id":2,"message_id":10}`, params["reply_parameters"])
+
+	// The reply can't be dropped when it's in another chat
+	_, err = b.Send(chatA, "text", &ReplyParams{ChatID: chatB, MessageID: 10}, AllowWithoutReply)
+	assert.ErrorContains(t, err, "same chat")
+	_, err = b.Send(chatA, "text", &ReplyParams{ChatID: chatB, MessageID: 10, AllowWithoutReply: true})
+	assert.ErrorContains(t, err, "same chat")
+
+	_, err = b.Send(chatA, "text", &ReplyParams{ChatID: 1, MessageID: 10}, AllowWithoutReply)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"chat_id":1,"message_id":10,"allow_sending_without_reply":true}`, params["reply_parameters"])
+	assert.NotContains(t, params, "allow_sending_without_reply")
+}
+
+func TestBotSendLegacyAllowWithoutReply(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	// No reply target
+	_, err = b.Send(&Chat{ID: 1}, "text", AllowWithoutReply)
+	require.NoError(t, err)
+	assert.Equal(t, "true", params["allow_sending_without_reply"])
+	assert.NotContains(t, params, "reply_parameters")
+
+	_, err = b.Send(&Chat{ID: 1}, "text", &SendOptions{
+		ReplyTo:           &Message{ID: 5},
+		ReplyParams:       &ReplyParams{MessageID: 10},
+		AllowWithoutReply: true,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message_id":10,"allow_sending_without_reply":true}`, params["reply_parameters"])
+	assert.NotContains(t, params, "allow_sending_without_reply")
+	assert.NotContains(t, params, "reply_to_message_id")
+}
+
+func TestBotCaptionAbove(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		if call.Method == "sendMediaGroup" {
+			return `{"ok":true,"result":[{"message_id":1,"chat":{"id":1}},{"message_id":2,"chat":{"id":1}}]}`
+		}
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1},"show_caption_above_media":true,"photo":[{"file_id":"photo"}]}}`
+	})
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Log: &LogConfig{Enable: true, Level: LogLevelDebug, Logger: logger}})
+	require.NoError(t, err)
+
+	to := &Chat{ID: 1}
+	photo := &Photo{File: File{FileID: "photo"}, Caption: "caption", CaptionAbove: true}
+	msg, err := b.Send(to, photo)
+	require.NoError(t, err)
+	assert.Equal(t, "true", params["show_caption_above_media"])
+	assert.True(t, msg.CaptionAbove)
+	assert.True(t, photo.CaptionAbove)
+
+	_, err = b.Send(to, &Video{File: File{FileID: "video"}}, CaptionAbove)
+	require.NoError(t, err)
+	assert.Equal(t, "true", params["show_caption_above_media"])
+
+	_, err = b.Send(to, &Document{File: File{FileID: "doc"}}, CaptionAbove)
+	require.NoError(t, err)
+	assert.NotContains(t, params, "show_caption_above_media")
+	assert.Contains(t, logger.GetOutput(), "not supported for document")
+
+	_, err = b.SendAlbum(to, Album{&Photo{File: File{FileID: "photo"}}, &Video{File: File{FileID: "video"}}}, CaptionAbove)
+	require.NoError(t, err)
+	assert.NotContains(t, params, "show_caption_above_media")
+	assert.Contains(t, params["media"], `"show_caption_above_media":true`)
+
+	_, err = b.EditMedia(msg, &Animation{File: File{FileID: "anim"}}, CaptionAbove)
+	require.NoError(t, err)
+	assert.NotContains(t, params, "show_caption_above_media")
+	assert.Contains(t, params["media"], `"show_caption_above_media":true`)
+
+	_, err = b.EditCaption(msg, "caption", CaptionAbove)
+	require.NoError(t, err)
+	assert.Equal(t, "true", params["show_caption_above_media"])
+}
+
+func TestBotRelayMany(t *testing.T) {
+	var calls []map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		calls = append(calls, call.Params)
+		if call.Params["chat_id"] == "3" {
+			return `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`
+		}
+		return `{"ok":true,"result":[{"message_id":10},{"message_id":11}]}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	to := []Recipient{&Chat{ID: 2}, &Chat{ID: 3}, nil}
+	results, err := b.RelayMany(to, &Chat{ID: -100}, []int{5, 4}, &SendOptions{RemoveCaption: true})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, []Message{{ID: 10}, {ID: 11}}, results[0].Messages)
+	assert.ErrorIs(t, results[1].Err, ErrBlockedByUser)
+	assert.Equal(t, ErrBadRecipient, results[2].Err)
+
+	require.Len(t, calls, 2)
+	assert.Equal(t, map[string]string{
+		"chat_id":        "2",
+		"from_chat_id":   "-100",
+		"message_ids":    "[4,5]",
+		"remove_caption": "true",
+	}, calls[0])
+
+	_, err = b.RelayMany(to, nil, []int{1})
+	assert.Equal(t, ErrBadRecipient, err)
+	_, err = b.RelayMany(to, &Chat{ID: -100}, nil)
+	assert.Error(t, err)
+
+	// CopyMany sends to the target chat from the chat of the messages
+	calls = nil
+	_, err = b.CopyMany(&Chat{ID: 2}, []Editable{StoredMessage{MessageID: "4", ChatID: -100}})
+	require.NoError(t, err)
+	assert.Equal(t, "2", calls[0]["chat_id"])
+	assert.Equal(t, "-100", calls[0]["from_chat_id"])
+}
+
+func TestBotSendSequence(t *testing.T) {
+	var (
+		texts   []string
+		flooded bool
+	)
+	srv := newTestAPI(t, func(call apiCall) string {
+		texts = append(texts, call.Params["text"])
+
+		switch call.Params["text"] {
+		case "two":
+			if !flooded {
+				flooded = true
+				return `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 0","parameters":{"retry_after":0}}`
+			}
+		case "bad":
+			return `{"ok":false,"error_code":400,"description":"Bad Request: text is empty"}`
+		}
+		return fmt.Sprintf(`{"ok":true,"result":{"message_id":%d,"chat":{"id":1}}}`, len(texts))
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	msgs, err := b.SendSequence(&Chat{ID: 1}, []string{"one", "two", "three"}, time.Millisecond, Silent)
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+	assert.Equal(t, []string{"one", "two", "two", "three"}, texts)
+	assert.Equal(t, 4, msgs[2].ID)
+
+	texts = nil
+	msgs, err = b.SendSequence(&Chat{ID: 1}, []string{"one", "bad", "three"})
+	assert.ErrorIs(t, err, ErrEmptyText)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, []string{"one", "bad"}, texts)
+
+	_, err = b.SendSequence(nil, []string{"one"})
+	assert.Equal(t, ErrBadRecipient, err)
+}
+
+func TestBotRespond(t *testing.T) {
+	var (
+		calls  int
+		answer func() string
+	)
+	srv := newTestAPI(t, func(apiCall) string {
+		calls++
+		return answer()
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	flooded := func(retryAfter int) func() string {
+		return func() string {
+			if calls == 1 {
+				return fmt.Sprintf(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after %d","parameters":{"retry_after":%d}}`, retryAfter, retryAfter)
+			}
+			return `{"ok":true,"result":true}`
+		}
+	}
+
+	answer = flooded(0)
+	require.NoError(t, b.Respond(&Callback{ID: "1"}))
+	assert.Equal(t, 2, calls)
+
+	// The query would expire while waiting
+	calls = 0
+	answer = flooded(maxCallbackRetryAfter + 1)
+	assert.IsType(t, FloodError{}, b.Respond(&Callback{ID: "1"}))
+	assert.Equal(t, 1, calls)
+
+	answer = func() string {
+		return `{"ok":false,"error_code":400,"description":"Bad Request: query is too old and response timeout expired or query ID is invalid"}`
+	}
+	err = b.NewContext(Update{Callback: &Callback{ID: "1"}}).Respond()
+	assert.ErrorIs(t, err, ErrCallbackExpired)
+	assert.ErrorIs(t, err, ErrQueryTooOld)
+}
+
+func TestBotCallbackFamily(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	var got []string
+	record := func(name string) HandlerFunc {
+		return func(c Context) error {
+			got = append(got, name+" "+c.Callback().Unique+" "+c.Data())
+			return nil
+		}
+	}
+	b.Handle(&InlineButton{Unique: "page"}, record("page"))
+	b.Handle(&InlineButton{Unique: "page_admin"}, record("admin"))
+	b.Handle(&InlineButton{Unique: "page_first"}, record("first"))
+	b.Handle(OnCallback, record("any"))
+
+	for _, data := range []string{
+		"\fpage_first|1",
+		"\fpage_next|2",
+		"\fpage_admin_next|3",
+		"\fpage-prev",
+		"\fpageant|4",
+	} {
+		b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	}
+
+	assert.Equal(t, []string{
+		"first page_first 1",
+		"page page_next page_next|2",
+		"admin page_admin_next page_admin_next|3",
+		"page page-prev page-prev",
+		"any  \fpageant|4",
+	}, got)
+}
+
+func TestBotIdentity(t *testing.T) {
+	srv := newTestAPI(t, func(apiCall) string {
+		return `{"ok":true,"result":{"id":42,"is_bot":true,"username":"MyBot"}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+	assert.Equal(t, &User{}, b.Identity())
+
+	var handled sync.WaitGroup
+	b.Handle("/start", func(c Context) error {
+		defer handled.Done()
+		c.IsMentioned()
+		return nil
+	})
+
+	// Handlers read the identity while it's refreshed
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		handled.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.RefreshIdentity()
+			assert.NoError(t, err)
+		}()
+		b.ProcessUpdate(Update{Message: &Message{Text: "/start", Chat: &Chat{ID: 1}}})
+	}
+	wg.Wait()
+	handled.Wait()
+
+	assert.Equal(t, &User{ID: 42, IsBot: true, Username: "MyBot"}, b.Identity())
+
+	// The bot bound to a context shares the identity
+	bound := b.withContext(context.Background())
+	b.setIdentity(&User{ID: 43})
+	assert.Equal(t, int64(43), bound.Identity().ID)
+	assert.Equal(t, int64(43), b.Me.ID)
+
+	// Me can still be set up in a literal
+	lit := Bot{Me: &User{ID: 1}}
+	assert.Equal(t, int64(1), lit.Me.ID)
+}
+
+func TestBotEditEntities(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		return `{"ok":true,"result":true}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, ParseMode: ModeHTML})
+	require.NoError(t, err)
+
+	text, entities, err := (&TextBuilder{}).Entity(EntityBold, "🙂 bold").Text(" plain").Build()
+	require.NoError(t, err)
+
+	inline := StoredMessage{MessageID: "inline"}
+	_, err = b.Edit(inline, text, entities)
+	assert.Equal(t, ErrTrueResult, err)
+	assert.Equal(t, "inline", params["inline_message_id"])
+	assert.Contains(t, params["entities"], `{"type":"bold","offset":0,"length":7`)
+	assert.NotContains(t, params, "parse_mode")
+
+	params = nil
+	_, err = b.Edit(inline, "🙂", entities)
+	assert.ErrorContains(t, err, "out of the text of length 2")
+	assert.Nil(t, params)
+
+	_, err = b.Edit(inline, text, entities, ModeMarkdownV2)
+	assert.ErrorIs(t, err, ErrEntitiesParseMode)
+	assert.Nil(t, params)
+}
+
+func TestBotURLUploadFallback(t *testing.T) {
+	var uploaded, fileName string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/media/photo.jpg":
+			w.Write([]byte("jpeg"))
+		case r.URL.Path == "/media/missing.jpg":
+			http.NotFound(w, r)
+		case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/"):
+			file, header, err := r.FormFile("photo")
+			if !assert.NoError(t, err) {
+				return
+			}
+			data, _ := io.ReadAll(file)
+			uploaded, fileName = string(data), header.Filename
+			w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1},"photo":[{"file_id":"uploaded"}]}}`))
+		default:
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`))
+		}
+	}))
+	defer srv.Close()
+
+	to := &Chat{ID: 1}
+	photo := func(name string) *Photo {
+		return &Photo{File: FromURL(srv.URL + "/media/" + name + "?size=large")}
+	}
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+	_, err = b.Send(to, photo("photo.jpg"))
+	assert.ErrorIs(t, err, ErrWrongFileID)
+
+	logger := NewCustomTestLogger()
+	b, err = NewBot(Settings{
+		URL:               srv.URL,
+		Offline:           true,
+		URLUploadFallback: true,
+		Log:               &LogConfig{Enable: true, Level: LogLevelInfo, Logger: logger},
+	})
+	require.NoError(t, err)
+
+	p := photo("photo.jpg")
+	_, err = b.Send(to, p)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", uploaded)
+	assert.Equal(t, "photo.jpg", fileName)
+	assert.Equal(t, "uploaded", p.FileID)
+	assert.Contains(t, logger.GetOutput(), "uploading it instead")
+
+	_, err = b.Send(to, photo("missing.jpg"))
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestBotCheckBusinessSetup(t *testing.T) {
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{Offline: true, Log: &LogConfig{Enable: true, Level: LogLevelWarn, Logger: logger}})
+	require.NoError(t, err)
+	b.Handle(OnBusinessMessage, func(c Context) error { return nil })
+
+	// The offline bot doesn't know its capabilities
+	b.checkBusinessSetup()
+	assert.Empty(t, logger.GetOutput())
+
+	b.setIdentity(&User{ID: 1, CanConnectToBusiness: true})
+	b.checkBusinessSetup()
+	assert.Empty(t, logger.GetOutput())
+
+	b.setIdentity(&User{ID: 1})
+	b.checkBusinessSetup()
+	assert.Contains(t, logger.GetOutput(), "Enable Business Mode")
+
+	logger = NewCustomTestLogger()
+	b, err = NewBot(Settings{Offline: true, Log: &LogConfig{Enable: true, Level: LogLevelWarn, Logger: logger}})
+	require.NoError(t, err)
+	b.setIdentity(&User{ID: 1})
+	b.checkBusinessSetup()
+	assert.Empty(t, logger.GetOutput())
+}
+
+func TestBotWebhookInfo(t *testing.T) {
+	srv := newTestAPI(t, func(apiCall) string {
+		return `{"ok":true,"result":{
+			"url":"https://example.com/hook",
+			"pending_update_count":3,
+			"last_error_date":1700000000,
+			"last_error_message":"Connection refused",
+			"max_connections":40,
+			"allowed_updates":["message"]
+		}}`
+	})
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Log: &LogConfig{Enable: true, Logger: logger}})
+	require.NoError(t, err)
+
+	info, err := b.WebhookInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", info.URL)
+	assert.Equal(t, 3, info.PendingUpdates)
+	assert.Equal(t, "Connection refused", info.LastErrorMessage)
+	assert.Equal(t, int64(1700000000), info.LastErrorDate().Unix())
+	assert.Equal(t, 40, info.MaxConnections)
+	assert.Equal(t, []string{"message"}, info.AllowedUpdates)
+
+	b.checkWebhook()
+	assert.Contains(t, logger.GetOutput(), "[WARN]")
+	assert.Contains(t, logger.GetOutput(), "Connection refused")
+}
+
+func TestSplitCallback(t *testing.T) {
+	tests := []struct {
+		data, unique, payload string
+		ok                    bool
+	}{
+		{"\funique", "unique", "", true},
+		{"\funique|payload", "unique", "payload", true},
+		{"\fun-iq_ue|pay|load", "un-iq_ue", "pay|load", true},
+		{"\f", "", "", false},
+		{"\funique|", "", "", false},
+		{"\fun ique|payload", "", "", false},
+		{"\funique|pay\nload", "", "", false},
+	}
+
+	for _, tt := range tests {
+		unique, payload, ok := splitCallback(tt.data)
+		assert.Equal(t, tt.ok, ok, tt.data)
+		assert.Equal(t, tt.unique, unique, tt.data)
+		assert.Equal(t, tt.payload, payload, tt.data)
+	}
+}
+
+func BenchmarkProcessUpdate(b *testing.B) {
+	bot, err := NewBot(Settings{Synchronous: true, Offline: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	nop := func(c Context) error { return nil }
+	bot.Handle("/start", nop)
+	bot.Handle(OnText, nop)
+	bot.Handle(&InlineButton{Unique: "unique"}, nop)
+
+	updates := map[string]func() Update{
+		"command": func() Update {
+			return Update{Message: &Message{Text: "/start payload"}}
+		},
+		"text": func() Update {
+			return Update{Message: &Message{Text: "hello there"}}
+		},
+		"callback": func() Update {
+			return Update{Callback: &Callback{Data: "\funique|payload"}}
+		},
+	}
+
+	for name, upd := range updates {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bot.ProcessUpdate(upd())
+			}
+		})
+	}
+}
+
 func TestBot(t *testing.T) {
 	if b == nil {
 		t.Skip("Cached bot instance is bad (probably wrong or empty TELEBOT_SECRET)")
@@ -102,5 +102,64 @@ func (b *Bot) BusinessConnection(id string) (*BusinessConnection, error) {
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, wrapError(err)
 	}
+
+	b.cacheBusinessConnection(resp.Result)
 	return resp.Result, nil
 }
+
+// cacheBusinessConnection remembers the connection, so that
+// replies on its behalf can be checked without a request.
+func (b *Bot) cacheBusinessConnection(conn *BusinessConnection) {
+	if conn == nil || conn.ID == "" {
+		return
+	}
+
+	b.businessMu.Lock()
+	defer b.businessMu.Unlock()
+	if b.business == nil {
+		b.business = make(map[string]*BusinessConnection)
+	}
+	b.business[conn.ID] = conn
+}
+
+// checkBusinessReply returns ErrBusinessCannotReply if the bot
+// isn't allowed to reply on behalf of the business account.
+// The connection is fetched if it isn't cached yet.
+func (b *Bot) checkBusinessReply(id string) error {
+	b.businessMu.Lock()
+	conn, ok := b.business[id]
+	b.businessMu.Unlock()
+
+	if !ok {
+		var err error
+		if conn, err = b.BusinessConnection(id); err != nil {
+			return err
+		}
+	}
+	if conn == nil || !conn.Enabled || !conn.CanReply {
+		return ErrBusinessCannotReply
+	}
+	return nil
+}
+
+// checkBusinessSetup warns if there are business handlers, but the
+// bot can't be connected to business accounts, so that the updates
+// would never arrive. Does nothing for an offline bot.
+func (b *Bot) checkBusinessSetup() {
+	if me := b.Identity(); me == nil || me.ID == 0 || me.CanConnectToBusiness {
+		return
+	}
+
+	for _, end := range []string{
+		OnBusinessConnection,
+		OnBusinessMessage,
+		OnEditedBusinessMessage,
+		OnDeletedBusinessMessages,
+	} {
+		if b.hasHandler(end) {
+			b.output.Warn("Business handlers are registered, but the bot can't connect to business accounts, " +
+				"so no business updates will arrive. Enable Business Mode for the bot in @BotFather.")
+			return
+		}
+	}
+}
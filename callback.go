@@ -39,6 +39,9 @@ type Callback struct {
 	// callback was fired. Sets immediately before the handling,
 	// while the Data field stores only with payload.
 	Unique string `json:"-"`
+
+	// stateToken is the CallbackStore token of the button state.
+	stateToken string
 }
 
 // MessageSig satisfies Editable interface.
@@ -0,0 +1,122 @@
+package telebot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxCallbackData is the maximum size of the callback data in bytes.
+const MaxCallbackData = 64
+
+// MarshalCallback packs the exported fields of the struct v into the
+// callback data of the button handled by the prefix endpoint, so the
+// handler of the "\f<prefix>" endpoint or &Btn{Unique: prefix} can read
+// them back with Context.CallbackUnmarshal:
+//
+//	data, err := b.MarshalCallback("buy", Order{ID: 42, Count: 2})
+//	markup.InlineKeyboard = [][]tele.InlineButton{{{Text: "Buy", Data: data}}}
+//
+// The fields are joined with "|" in the order of declaration, the
+// supported types are the ones of BindArgs. If the packed data exceeds
+// MaxCallbackData, or a string field contains "|", v is put to the
+// CallbackStore as JSON instead, and only its token is sent.
+func (b *Bot) MarshalCallback(prefix string, v any) (string, error) {
+	data, ok, err := packCallback(v)
+	if err != nil {
+		return "", err
+	}
+
+	// Format: "\f<prefix>|<field>|<field>..."
+	data = "\f" + prefix + "|" + data
+	if ok && len(data) <= MaxCallbackData {
+		return data, nil
+	}
+
+	// Format: "\f<prefix>\v<token>"
+	token, err := b.storeCallbackState(v)
+	if err != nil {
+		return "", err
+	}
+	return "\f" + prefix + "\v" + token, nil
+}
+
+// packCallback joins the struct fields with "|". It reports false
+// if a field can't be packed and has to be stored.
+func packCallback(v any) (string, bool, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return "", false, fmt.Errorf("telebot: MarshalCallback needs a struct, got %T", v)
+	}
+
+	var fields []string
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		var field string
+		switch fv.Kind() {
+		case reflect.String:
+			field = fv.String()
+			if strings.ContainsAny(field, "|\v") {
+				return "", false, nil
+			}
+		case reflect.Bool:
+			field = "0"
+			if fv.Bool() {
+				field = "1"
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if fv.Type() == durationType {
+				field = fv.Interface().(fmt.Stringer).String()
+			} else {
+				field = strconv.FormatInt(fv.Int(), 10)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field = strconv.FormatUint(fv.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			field = strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits())
+		default:
+			return "", false, fmt.Errorf("telebot: MarshalCallback doesn't support fields of type %s", fv.Type())
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(fields, "|"), true, nil
+}
+
+// unpackCallback sets the struct fields dest points to from
+// the data packed by packCallback.
+func unpackCallback(data string, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("telebot: CallbackUnmarshal needs a pointer to struct, got %T", dest)
+	}
+	v = v.Elem()
+
+	fields := strings.Split(data, "|")
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		if len(fields) == 0 {
+			return &ArgError{Name: strings.ToLower(t.Field(i).Name), Reason: "is missing"}
+		}
+
+		field := fields[0]
+		fields = fields[1:]
+		if err := setArg(v.Field(i), field); err != nil {
+			var argErr *ArgError
+			if errors.As(err, &argErr) {
+				argErr.Name, argErr.Value = strings.ToLower(t.Field(i).Name), field
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package telebot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// patternRoute is a handler registered with HandleCallback.
+type patternRoute struct {
+	pattern string
+	rx      *regexp.Regexp
+	names   []string
+	handler HandlerFunc
+}
+
+var patternParamRx = regexp.MustCompile(`\{(\w+)\}`)
+
+// HandleCallback adds the handler of the callback queries whose data
+// matches the pattern, e.g. "item:{id}:{action}". The {name} parameters
+// match the non-empty parts of the data between the literal parts of the
+// pattern, and are available with Context.Param. Make the buttons with
+// ReplyMarkup.DataPattern:
+//
+//	b.HandleCallback("item:{id}:{action}", func(c tele.Context) error {
+//		return act(c.Param("id"), c.Param("action"))
+//	})
+//
+// The pattern handlers are tried in the order of registration, after
+// the "\f<unique>" endpoints and before OnCallback.
+func (b *Bot) HandleCallback(pattern string, h HandlerFunc, m ...MiddlewareFunc) {
+	var (
+		expr  strings.Builder
+		names []string
+		last  int
+	)
+	expr.WriteString("^")
+	for _, loc := range patternParamRx.FindAllStringSubmatchIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		expr.WriteString("(.+?)")
+		names = append(names, pattern[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	expr.WriteString("$")
+
+	route := patternRoute{
+		pattern: pattern,
+		rx:      regexp.MustCompile(expr.String()),
+		names:   names,
+		handler: b.wrapHandler("handler:callback:"+pattern, h, m),
+	}
+
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+	b.patterns = append(b.patterns, route)
+}
+
+// handleCallbackPattern runs the first pattern handler matching the data.
+func (b *Bot) handleCallbackPattern(c Context, data string) bool {
+	b.routesMu.RLock()
+	patterns := b.patterns
+	b.routesMu.RUnlock()
+
+	for _, r := range patterns {
+		matches := r.rx.FindStringSubmatch(data)
+		if matches == nil {
+			continue
+		}
+		if nc, ok := c.(*nativeContext); ok {
+			params := make(map[string]string, len(r.names))
+			for i, name := range r.names {
+				params[name] = matches[i+1]
+			}
+			nc.setParams(params)
+		}
+		b.runHandler(r.handler, c)
+		return true
+	}
+	return false
+}
+
+// FillPattern replaces the {name} parameters of the callback pattern
+// with the args in order, see Bot.HandleCallback. The missing args
+// leave the parameters as is.
+func FillPattern(pattern string, args ...any) string {
+	i := 0
+	return patternParamRx.ReplaceAllStringFunc(pattern, func(param string) string {
+		if i >= len(args) {
+			return param
+		}
+		i++
+		return fmt.Sprint(args[i-1])
+	})
+}
@@ -0,0 +1,125 @@
+package telebot
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CallbackStateTTL is the time the callback button states are kept for.
+const CallbackStateTTL = 7 * 24 * time.Hour
+
+// CallbackStore keeps the states of the callback buttons on the bot
+// side, so they aren't limited by 64 bytes of callback data. Only a short
+// token of the state is sent to Telegram. See Btn.WithState.
+//
+// By default, the states are kept in the bot Store, which is in-memory
+// unless Settings.Store is set, so they are lost on restart. Supply
+// a persistent store to keep the buttons working.
+type CallbackStore interface {
+	// Set stores the state by the token for the given time.
+	Set(token string, state []byte, ttl time.Duration) error
+
+	// Get returns the state by the token. It returns
+	// ErrCallbackStateNotFound if the state is missing or expired.
+	Get(token string) ([]byte, error)
+
+	// Expire removes the state by the token.
+	Expire(token string) error
+}
+
+// CallbackStore sets the store of the callback button states.
+func (b *Bot) CallbackStore(store CallbackStore) {
+	b.callbacksMu.Lock()
+	defer b.callbacksMu.Unlock()
+	b.callbacks = store
+}
+
+// callbackStore returns the current callback store, and creates
+// the one backed by the bot Store if needed.
+func (b *Bot) callbackStore() CallbackStore {
+	b.callbacksMu.Lock()
+	defer b.callbacksMu.Unlock()
+
+	if b.callbacks == nil {
+		b.callbacks = NewCallbackStore(b.Store())
+	}
+	return b.callbacks
+}
+
+// storeCallbackState puts the button state to the store
+// and returns its token.
+func (b *Bot) storeCallbackState(state any) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	var raw [8]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", wrapError(err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw[:])
+	if err := b.callbackStore().Set(token, data, CallbackStateTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// storeResultStates stores the button states of the inline result,
+// which embeds ResultBase, so the error can be returned before
+// Result.Process is called.
+func (b *Bot) storeResultStates(r Result) error {
+	rm, ok := r.(interface{ replyMarkup() *ReplyMarkup })
+	if !ok || rm.replyMarkup() == nil {
+		return nil
+	}
+	return b.storeButtonStates(rm.replyMarkup().InlineKeyboard)
+}
+
+// CallbackStoreNamespace is the Store namespace of the callback states.
+const CallbackStoreNamespace = "callbacks"
+
+// NewCallbackStore returns a CallbackStore keeping the states
+// in the store under CallbackStoreNamespace.
+func NewCallbackStore(store Store) CallbackStore {
+	return storeCallbacks{store}
+}
+
+type storeCallbacks struct {
+	store Store
+}
+
+func (s storeCallbacks) Set(token string, state []byte, ttl time.Duration) error {
+	return s.store.Set(CallbackStoreNamespace, token, state, ttl)
+}
+
+func (s storeCallbacks) Get(token string) ([]byte, error) {
+	data, err := s.store.Get(CallbackStoreNamespace, token)
+	if errors.Is(err, ErrStoreKeyNotFound) {
+		return nil, ErrCallbackStateNotFound
+	}
+	return data, err
+}
+
+func (s storeCallbacks) Expire(token string) error {
+	return s.store.Delete(CallbackStoreNamespace, token)
+}
+
+// MemoryCallbackStore is a bounded in-memory CallbackStore, a separate
+// MemoryStore for the callback states only. When it's full, the states
+// closest to expiration are evicted first.
+type MemoryCallbackStore struct {
+	storeCallbacks
+	mem *MemoryStore
+}
+
+// NewMemoryCallbackStore creates a store keeping up to max states,
+// DefaultMaxStoreKeys if max is zero.
+func NewMemoryCallbackStore(max int) *MemoryCallbackStore {
+	mem := NewMemoryStore(max)
+	return &MemoryCallbackStore{storeCallbacks: storeCallbacks{mem}, mem: mem}
+}
@@ -0,0 +1,167 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubClock struct {
+	realClock
+	now time.Time
+}
+
+func (c *stubClock) Now() time.Time {
+	return c.now
+}
+
+func TestMemoryCallbackStore(t *testing.T) {
+	clock := &stubClock{now: time.Now()}
+	s := NewMemoryCallbackStore(2)
+	s.mem.clock = clock
+
+	require.NoError(t, s.Set("a", []byte("1"), time.Minute))
+	require.NoError(t, s.Set("b", []byte("2"), time.Hour))
+
+	data, err := s.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), data)
+
+	// The closest to expiration is evicted
+	require.NoError(t, s.Set("c", []byte("3"), time.Hour))
+	_, err = s.Get("a")
+	assert.Equal(t, ErrCallbackStateNotFound, err)
+	assert.Len(t, s.mem.entries, 2)
+
+	clock.now = clock.now.Add(2 * time.Hour)
+	_, err = s.Get("b")
+	assert.Equal(t, ErrCallbackStateNotFound, err)
+
+	require.NoError(t, s.Expire("c"))
+	assert.Empty(t, s.mem.entries)
+}
+
+func TestCallbackState(t *testing.T) {
+	var params map[string]string
+	srv := newTestAPI(t, func(call apiCall) string {
+		params = call.Params
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	type state struct {
+		Page  int    `json:"page"`
+		Query string `json:"query"`
+	}
+
+	r := &ReplyMarkup{}
+	btn := r.Data("Next", "next", "1").WithState(state{Page: 2, Query: strings.Repeat("q", 100)})
+	r.Inline(r.Row(btn))
+
+	_, err = b.Send(&Chat{ID: 1}, "text", r)
+	require.NoError(t, err)
+
+	var markup ReplyMarkup
+	require.NoError(t, json.Unmarshal([]byte(params["reply_markup"]), &markup))
+
+	data := markup.InlineKeyboard[0][0].Data
+	assert.True(t, strings.HasPrefix(data, "\fnext|1\v"))
+	assert.LessOrEqual(t, len(data), 64)
+
+	var (
+		got      state
+		payload  string
+		stateErr error
+	)
+	b.Handle(&btn, func(c Context) error {
+		payload = c.Data()
+		stateErr = c.CallbackState(&got)
+		return nil
+	})
+
+	b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	require.NoError(t, stateErr)
+	assert.Equal(t, "1", payload)
+	assert.Equal(t, state{Page: 2, Query: strings.Repeat("q", 100)}, got)
+
+	// A restarted bot doesn't know the state
+	b.CallbackStore(NewMemoryCallbackStore(0))
+	b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	assert.Equal(t, ErrCallbackStateNotFound, stateErr)
+	assert.Equal(t, "1", payload)
+
+	// The button with a state which isn't stored is never sent
+	markup = ReplyMarkup{}
+	b.CallbackStore(failingCallbackStore{})
+	r.Inline(r.Row(r.Data("Next", "next", "1").WithState(state{Page: 3})))
+	_, err = b.Send(&Chat{ID: 1}, "text", r)
+	assert.ErrorIs(t, err, errStoreDown)
+	assert.Nil(t, markup.InlineKeyboard)
+}
+
+var errStoreDown = errors.New("store is down")
+
+type failingCallbackStore struct{}
+
+func (failingCallbackStore) Set(string, []byte, time.Duration) error { return errStoreDown }
+func (failingCallbackStore) Get(string) ([]byte, error)              { return nil, errStoreDown }
+func (failingCallbackStore) Expire(string) error                     { return errStoreDown }
+
+func TestMarshalCallback(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	type order struct {
+		ID    int64
+		Count uint8
+		Gift  bool
+		Note  string
+		Delay time.Duration
+	}
+
+	var (
+		got      order
+		unpacked error
+	)
+	b.Handle(&Btn{Unique: "buy"}, func(c Context) error {
+		got = order{}
+		unpacked = c.CallbackUnmarshal(&got)
+		return nil
+	})
+
+	short := order{ID: 42, Count: 2, Gift: true, Note: "hi", Delay: time.Minute}
+	data, err := b.MarshalCallback("buy", short)
+	require.NoError(t, err)
+	assert.Equal(t, "\fbuy|42|2|1|hi|1m0s", data)
+
+	b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	require.NoError(t, unpacked)
+	assert.Equal(t, short, got)
+
+	for _, long := range []order{
+		{ID: 1, Note: strings.Repeat("n", 100)},
+		{ID: 1, Note: "a|b"},
+	} {
+		data, err = b.MarshalCallback("buy", &long)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(data, "\fbuy\v"))
+		assert.LessOrEqual(t, len(data), MaxCallbackData)
+
+		b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+		require.NoError(t, unpacked)
+		assert.Equal(t, long, got)
+	}
+
+	b.ProcessUpdate(Update{Callback: &Callback{Data: "\fbuy|x"}})
+	assert.EqualError(t, unpacked, `id must be a whole number, got "x"`)
+
+	_, err = b.MarshalCallback("buy", 1)
+	assert.Error(t, err)
+}
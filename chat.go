@@ -48,6 +48,9 @@ type Chat struct {
 	LastName  string `json:"last_name"`
 	Username  string `json:"username"`
 
+	// (Optional) True, if the chat is the direct messages chat of a channel.
+	DirectMessages bool `json:"is_direct_messages,omitempty"`
+
 	// Returns only in getChat
 	Bio                            string               `json:"bio,omitempty"`
 	Photo                          *ChatPhoto           `json:"photo,omitempty"`
@@ -82,11 +85,79 @@ type Chat struct {
 	BusinessIntro                  BusinessIntro        `json:"business_intro,omitempty"`
 	BusinessLocation               BusinessLocation     `json:"business_location,omitempty"`
 	BusinessOpeningHours           BusinessOpeningHours `json:"business_opening_hours,omitempty"`
+	ParentChat                     *Chat                `json:"parent_chat,omitempty"`
+	AutoDeleteTime                 int                  `json:"message_auto_delete_time,omitempty"`
 }
 
-// Recipient returns chat ID (see Recipient interface).
+// ReactionAllowed reports whether the reaction is available in the chat,
+// as returned by ChatByID. Chats which don't limit the reactions allow
+// all emoji reactions.
+func (c *Chat) ReactionAllowed(r Reaction) bool {
+	if c.Reactions == nil {
+		return r.Type == ReactionTypeEmoji
+	}
+	for _, allowed := range c.Reactions {
+		if allowed == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Recipient returns chat ID (see Recipient interface). The ID is
+// normalized if the chat type is known, see NormalizeChatID.
 func (c *Chat) Recipient() string {
-	return strconv.FormatInt(c.ID, 10)
+	return strconv.FormatInt(NormalizeChatID(c.ID, c.Type), 10)
+}
+
+// channelIDShift is the magnitude of the -100 prefix
+// of the supergroup and channel IDs.
+const channelIDShift = 1_000_000_000_000
+
+// NormalizeChatID returns the chat ID in the Bot API form for
+// the chat type. Telegram apps and other tools often show the IDs
+// without the sign or prefix, while the Bot API expects:
+//
+//   - a positive ID for users, as is;
+//   - a negative ID for basic groups, e.g. -123456789;
+//   - a negative ID with the -100 prefix for supergroups and
+//     channels, e.g. -1001234567890.
+//
+// So a positive group ID gets the minus, and a positive supergroup
+// or channel ID gets the -100 prefix, unless it already has it without
+// the minus, i.e. it's greater than 1000000000000, e.g. 1001234567890.
+// Negative IDs, private chats and unknown types are left as is.
+func NormalizeChatID(id int64, chatType ChatType) int64 {
+	if id <= 0 {
+		return id
+	}
+
+	switch chatType {
+	case ChatGroup:
+		return -id
+	case ChatSuperGroup, ChatChannel, ChatChannelPrivate:
+		if id > channelIDShift {
+			return -id
+		}
+		return -(channelIDShift + id)
+	default:
+		return id
+	}
+}
+
+// IsDirectMessages says whether the chat is the direct messages
+// chat of a channel, where the users write to the channel.
+func (c *Chat) IsDirectMessages() bool {
+	return c.DirectMessages
+}
+
+// DirectMessagesTopic describes a topic of a direct messages chat.
+type DirectMessagesTopic struct {
+	// Unique identifier of the topic.
+	TopicID int64 `json:"topic_id"`
+
+	// (Optional) Information about the user that created the topic.
+	User *User `json:"user,omitempty"`
 }
 
 // ChatType represents one of the possible chat types.
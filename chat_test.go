@@ -1,9 +1,11 @@
 package telebot
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChat(t *testing.T) {
@@ -19,3 +21,64 @@ func TestChat(t *testing.T) {
 	assert.Equal(t, "1", chat.Recipient())
 	assert.Equal(t, "1", chatID.Recipient())
 }
+
+func TestNormalizeChatID(t *testing.T) {
+	for _, tc := range []struct {
+		id       int64
+		chatType ChatType
+		want     int64
+	}{
+		{42, ChatPrivate, 42},
+		{42, "", 42},
+		{123456789, ChatGroup, -123456789},
+		{-123456789, ChatGroup, -123456789},
+		{1234567890, ChatSuperGroup, -1001234567890},
+		{1234567890, ChatChannel, -1001234567890},
+		{1001234567890, ChatChannel, -1001234567890},
+		{-1001234567890, ChatSuperGroup, -1001234567890},
+	} {
+		assert.Equal(t, tc.want, NormalizeChatID(tc.id, tc.chatType), "%d %s", tc.id, tc.chatType)
+	}
+
+	assert.Equal(t, "-1001234567890", (&Chat{ID: 1234567890, Type: ChatSuperGroup}).Recipient())
+	assert.Equal(t, "1234567890", (&Chat{ID: 1234567890}).Recipient())
+
+	srv := newTestAPI(t, func(call apiCall) string {
+		if strings.HasPrefix(call.Params["chat_id"], "-") {
+			return `{"ok":true,"result":{"message_id":1,"chat":{"id":-1001234567890}}}`
+		}
+		return `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	})
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Log: &LogConfig{Enable: true, Level: LogLevelWarn, Logger: logger}})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = b.Send(&Chat{ID: 1234567890, Type: ChatChannel}, "text")
+		require.NoError(t, err)
+		_, err = b.Send(ChatID(123456789), "text")
+		assert.Equal(t, ErrChatNotFound, err)
+	}
+	assert.Equal(t, 1, strings.Count(logger.GetOutput(), "Chat 1234567890 of type channel has a positive ID, sending to -1001234567890 instead"))
+	assert.Equal(t, 1, strings.Count(logger.GetOutput(), "Chat 123456789 is not found, the groups have negative IDs"))
+}
+
+func TestChatReactionAllowed(t *testing.T) {
+	like := Reaction{Type: ReactionTypeEmoji, Emoji: "👍"}
+	custom := Reaction{Type: ReactionTypeCustomEmoji, CustomEmojiID: "5368324170671202286"}
+
+	chat := &Chat{}
+	assert.True(t, chat.ReactionAllowed(like))
+	assert.False(t, chat.ReactionAllowed(custom))
+
+	chat.Reactions = []Reaction{custom}
+	assert.False(t, chat.ReactionAllowed(like))
+	assert.True(t, chat.ReactionAllowed(custom))
+
+	assert.NoError(t, like.validate())
+	assert.NoError(t, custom.validate())
+	assert.Error(t, Reaction{Type: ReactionTypeCustomEmoji, CustomEmojiID: "abc"}.validate())
+	assert.Error(t, Reaction{Type: ReactionTypeEmoji}.validate())
+	assert.NoError(t, Reaction{Type: ReactionTypePaid}.validate())
+	assert.Error(t, Reaction{Type: "unknown"}.validate())
+}
@@ -0,0 +1,72 @@
+package telebot
+
+import "strconv"
+
+// Chatter sends messages to the chat of the context it's taken from,
+// keeping the topic and the business connection of the current message.
+// See Context.Chatter.
+type Chatter struct {
+	b   API
+	to  Recipient
+	msg *Message
+}
+
+// Chat returns the chat the chatter sends to.
+func (ch Chatter) Chat() Recipient {
+	return ch.to
+}
+
+// Send sends a message to the current chat. See Send from bot.go.
+func (ch Chatter) Send(what any, opts ...any) (*Message, error) {
+	if ch.to == nil {
+		return nil, ErrBadRecipient
+	}
+	return ch.b.Send(ch.to, what, ch.scope(opts)...)
+}
+
+// Reply replies to the current message. See Reply from bot.go.
+func (ch Chatter) Reply(what any, opts ...any) (*Message, error) {
+	if ch.msg == nil {
+		return nil, ErrBadContext
+	}
+	return ch.b.Reply(ch.msg, what, ch.scope(opts)...)
+}
+
+// Album sends an album to the current chat. See SendAlbum from bot.go.
+func (ch Chatter) Album(a Album, opts ...any) ([]Message, error) {
+	if ch.to == nil {
+		return nil, ErrBadRecipient
+	}
+	return ch.b.SendAlbum(ch.to, a, ch.scope(opts)...)
+}
+
+// Action sends a chat action to the current chat. See Notify from bot.go.
+func (ch Chatter) Action(action ChatAction) error {
+	if ch.to == nil {
+		return ErrBadRecipient
+	}
+
+	params := map[string]string{
+		"chat_id": ch.to.Recipient(),
+		"action":  string(action),
+	}
+	if m := ch.msg; m != nil {
+		if m.TopicMessage && m.ThreadID != 0 {
+			params["message_thread_id"] = strconv.Itoa(m.ThreadID)
+		}
+		if m.BusinessConnectionID != "" {
+			params["business_connection_id"] = m.BusinessConnectionID
+		}
+	}
+
+	_, err := ch.b.Raw("sendChatAction", params)
+	return err
+}
+
+func (ch Chatter) scope(opts []any) []any {
+	opts = inheritOpts(ch.msg, opts)
+	if ch.msg != nil && ch.msg.BusinessConnectionID != "" {
+		opts = append(opts, &BusinessConnection{ID: ch.msg.BusinessConnectionID})
+	}
+	return opts
+}
@@ -1,6 +1,13 @@
 package telebot
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
 
 // Command represents a bot command.
 type Command struct {
@@ -12,6 +19,69 @@ type Command struct {
 	Description string `json:"description"`
 }
 
+var commandTextRx = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// ParseCommand parses the command in the BotFather format,
+// "command - description", and validates it. The leading slash
+// of the command is optional.
+func ParseCommand(s string) (Command, error) {
+	cmd, err := parseCommand(s)
+	if err != nil {
+		return Command{}, fmt.Errorf("telebot: %w", err)
+	}
+	return cmd, nil
+}
+
+func parseCommand(s string) (Command, error) {
+	text, desc, ok := strings.Cut(s, " - ")
+	if !ok {
+		text, desc, ok = strings.Cut(s, "-")
+	}
+	if !ok {
+		return Command{}, errors.New(`command must be in the format "command - description"`)
+	}
+
+	cmd := Command{
+		Text:        strings.TrimPrefix(strings.TrimSpace(text), "/"),
+		Description: strings.TrimSpace(desc),
+	}
+	if !commandTextRx.MatchString(cmd.Text) {
+		return Command{}, fmt.Errorf("invalid command %q, must be 1-32 lowercase letters, digits or underscores", cmd.Text)
+	}
+	if n := utf8.RuneCountInString(cmd.Description); n < 3 || n > 256 {
+		return Command{}, fmt.Errorf("description of command %q must be 3-256 characters, got %d", cmd.Text, n)
+	}
+	return cmd, nil
+}
+
+// ParseCommands parses the commands in the BotFather format, one per
+// line, so that the list can be loaded from a file and passed to
+// SetCommands. Blank lines and lines starting with # are skipped.
+// The error tells the line of the first malformed command.
+func ParseCommands(text string) ([]Command, error) {
+	var (
+		commands []Command
+		seen     = make(map[string]int)
+	)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cmd, err := parseCommand(line)
+		if err != nil {
+			return nil, fmt.Errorf("telebot: line %d: %w", i+1, err)
+		}
+		if prev, ok := seen[cmd.Text]; ok {
+			return nil, fmt.Errorf("telebot: line %d: command %q is already defined on line %d", i+1, cmd.Text, prev)
+		}
+		seen[cmd.Text] = i + 1
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
+
 // CommandParams controls parameters for commands-related methods (setMyCommands, deleteMyCommands and getMyCommands).
 type CommandParams struct {
 	Commands     []Command     `json:"commands,omitempty"`
@@ -83,3 +153,136 @@ func extractCommandsParams(opts ...any) (params CommandParams) {
 	}
 	return
 }
+
+// CommandSet is a list of commands for a scope and a user language,
+// see ApplyCommandSets.
+type CommandSet struct {
+	Scope        CommandScope
+	LanguageCode string
+	Commands     []Command
+}
+
+// String returns the scope and the language of the set.
+func (s CommandSet) String() string {
+	str := string(s.Scope.Type)
+	if str == "" {
+		str = CommandScopeDefault
+	}
+	if s.Scope.ChatID != 0 {
+		str += fmt.Sprintf(" chat=%d", s.Scope.ChatID)
+	}
+	if s.Scope.UserID != 0 {
+		str += fmt.Sprintf(" user=%d", s.Scope.UserID)
+	}
+	if s.LanguageCode != "" {
+		str += " lang=" + s.LanguageCode
+	}
+	return str
+}
+
+func (s CommandSet) params(commands []Command) CommandParams {
+	params := CommandParams{Commands: commands, LanguageCode: s.LanguageCode}
+	if s.Scope.Type != "" {
+		scope := s.Scope
+		params.Scope = &scope
+	}
+	return params
+}
+
+// CommandSetError describes a command set which failed to apply.
+type CommandSetError struct {
+	Set CommandSet
+	Err error
+}
+
+// CommandSetsError is returned by ApplyCommandSets when some
+// of the command sets failed to apply.
+type CommandSetsError struct {
+	// Failed are the sets that couldn't be applied.
+	Failed []CommandSetError
+
+	// NotRestored are the applied sets that couldn't be rolled back,
+	// so they keep the new commands.
+	NotRestored []CommandSetError
+}
+
+// Error implements error interface.
+func (err *CommandSetsError) Error() string {
+	failed := make([]string, 0, len(err.Failed))
+	for _, f := range err.Failed {
+		failed = append(failed, fmt.Sprintf("%s (%v)", f.Set, f.Err))
+	}
+
+	msg := "telebot: failed to set commands for " + strings.Join(failed, ", ")
+	if len(err.NotRestored) > 0 {
+		restore := make([]string, 0, len(err.NotRestored))
+		for _, f := range err.NotRestored {
+			restore = append(restore, f.Set.String())
+		}
+		msg += "; failed to restore commands for " + strings.Join(restore, ", ")
+	}
+	return msg
+}
+
+// Unwrap returns the errors of the failed sets.
+func (err *CommandSetsError) Unwrap() []error {
+	errs := make([]error, 0, len(err.Failed)+len(err.NotRestored))
+	for _, f := range err.Failed {
+		errs = append(errs, f.Err)
+	}
+	for _, f := range err.NotRestored {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// ApplyCommandSets changes the commands for several scopes and languages
+// at once. The current commands are fetched first, and if any set fails
+// to apply, the already applied ones are restored, so the users of
+// different scopes don't end up with inconsistent commands.
+//
+// On a partial failure, *CommandSetsError is returned.
+func (b *Bot) ApplyCommandSets(sets []CommandSet) error {
+	backup := make([][]Command, len(sets))
+	for i, set := range sets {
+		opts := []any{set.LanguageCode}
+		if set.Scope.Type != "" {
+			opts = append(opts, set.Scope)
+		}
+
+		commands, err := b.Commands(opts...)
+		if err != nil {
+			return err
+		}
+		backup[i] = commands
+	}
+
+	setsErr := &CommandSetsError{}
+	applied := make([]int, 0, len(sets))
+
+	for i, set := range sets {
+		if _, err := b.Raw("setMyCommands", set.params(set.Commands)); err != nil {
+			setsErr.Failed = append(setsErr.Failed, CommandSetError{Set: set, Err: err})
+			continue
+		}
+		applied = append(applied, i)
+	}
+	if len(setsErr.Failed) == 0 {
+		return nil
+	}
+
+	for _, i := range applied {
+		var (
+			set    = sets[i]
+			method = "setMyCommands"
+		)
+		if len(backup[i]) == 0 {
+			method = "deleteMyCommands"
+		}
+		if _, err := b.Raw(method, set.params(backup[i])); err != nil {
+			setsErr.NotRestored = append(setsErr.NotRestored, CommandSetError{Set: set, Err: err})
+		}
+	}
+
+	return setsErr
+}
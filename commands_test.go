@@ -0,0 +1,122 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCommandSets(t *testing.T) {
+	var (
+		commands = map[string][]Command{
+			"default": {{Text: "old", Description: "Old command"}},
+		}
+		failScope string
+	)
+	srv := newTestAPI(t, func(call apiCall) string {
+		var params CommandParams
+		assert.NoError(t, json.Unmarshal(call.Body, &params))
+
+		scope := CommandScopeDefault
+		if params.Scope != nil {
+			scope = params.Scope.Type
+		}
+		key := scope + params.LanguageCode
+
+		switch call.Method {
+		case "getMyCommands":
+			data, _ := json.Marshal(commands[key])
+			return `{"ok":true,"result":` + string(data) + `}`
+		case "setMyCommands":
+			if scope == failScope {
+				return `{"ok":false,"error_code":400,"description":"Bad Request: BOT_COMMAND_INVALID"}`
+			}
+			commands[key] = params.Commands
+		case "deleteMyCommands":
+			delete(commands, key)
+		}
+		return `{"ok":true,"result":true}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	sets := []CommandSet{
+		{Commands: []Command{{Text: "start", Description: "Start"}}},
+		{
+			Scope:        CommandScope{Type: CommandScopeAllPrivateChats},
+			LanguageCode: "en",
+			Commands:     []Command{{Text: "help", Description: "Help"}},
+		},
+		{
+			Scope:    CommandScope{Type: CommandScopeAllChatAdmin},
+			Commands: []Command{{Text: "ban", Description: "Ban"}},
+		},
+	}
+
+	failScope = CommandScopeAllChatAdmin
+	err = b.ApplyCommandSets(sets)
+
+	var setsErr *CommandSetsError
+	require.True(t, errors.As(err, &setsErr))
+	require.Len(t, setsErr.Failed, 1)
+	assert.Equal(t, CommandScopeAllChatAdmin, setsErr.Failed[0].Set.Scope.Type)
+	assert.Empty(t, setsErr.NotRestored)
+	assert.ErrorContains(t, err, "all_chat_administrators")
+
+	// The applied sets are restored
+	assert.Equal(t, map[string][]Command{
+		"default": {{Text: "old", Description: "Old command"}},
+	}, commands)
+
+	failScope = ""
+	require.NoError(t, b.ApplyCommandSets(sets))
+	assert.Len(t, commands, 3)
+	assert.Equal(t, "help", commands["all_private_chatsen"][0].Text)
+
+	assert.Equal(t, "chat chat=1 lang=en",
+		CommandSet{Scope: CommandScope{Type: CommandScopeChat, ChatID: 1}, LanguageCode: "en"}.String())
+}
+
+func TestParseCommands(t *testing.T) {
+	cmd, err := ParseCommand("/start - Start the bot")
+	require.NoError(t, err)
+	assert.Equal(t, Command{Text: "start", Description: "Start the bot"}, cmd)
+
+	for _, s := range []string{
+		"start",
+		"Start - Start the bot",
+		"st-art - Start the bot",
+		" - Start the bot",
+		"start - Go",
+		"start - " + strings.Repeat("я", 257),
+		strings.Repeat("a", 33) + " - Start the bot",
+	} {
+		_, err := ParseCommand(s)
+		assert.Error(t, err, s)
+	}
+
+	commands, err := ParseCommands(`
+# Public commands
+start - Start the bot
+help - Show the help
+
+settings_v2 - Open the settings
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Command{
+		{Text: "start", Description: "Start the bot"},
+		{Text: "help", Description: "Show the help"},
+		{Text: "settings_v2", Description: "Open the settings"},
+	}, commands)
+
+	_, err = ParseCommands("start - Start the bot\nhelp\n")
+	assert.EqualError(t, err, `telebot: line 2: command must be in the format "command - description"`)
+
+	_, err = ParseCommands("start - Start the bot\nstart - Start again")
+	assert.EqualError(t, err, `telebot: line 2: command "start" is already defined on line 1`)
+}
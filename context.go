@@ -1,10 +1,16 @@
 package telebot
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"io"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf16"
 )
 
 // HandlerFunc represents a handler function, which is
@@ -20,26 +26,78 @@ func NewContext(b API, u Update) Context {
 	}
 }
 
+var contextPool = sync.Pool{
+	New: func() any { return new(nativeContext) },
+}
+
+// acquireContext takes a native context from the pool and fills it
+// with the passed update. It must be followed by releaseContext.
+func acquireContext(b API, u Update) *nativeContext {
+	c := contextPool.Get().(*nativeContext)
+	c.b = b
+	c.u = u
+	return c
+}
+
+// releaseContext resets the context and puts it back to the pool,
+// so no data leaks from one update to another.
+func releaseContext(c *nativeContext) {
+	c.reset()
+	contextPool.Put(c)
+}
+
 // Context wraps an update and represents the context of current event.
 type Context interface {
-	// Bot returns the bot instance.
+	// Bot returns the bot instance. It's an escape hatch for the calls
+	// the context doesn't cover, so the recipient and the options must
+	// be passed explicitly. Use Chatter to send to the current chat.
 	Bot() API
 
+	// Chatter returns a sender bound to the current chat, topic and
+	// business connection. It's cheap to get, so there is no need to
+	// keep it around.
+	Chatter() Chatter
+
 	// Update returns the original update.
 	Update() Update
 
 	// Message returns stored message if such presented.
 	Message() *Message
 
+	// EffectiveMessage returns the message the update carries, looking
+	// in this order: message, edited message, channel post, edited
+	// channel post, business message, edited business message and
+	// the callback message. Unlike Message, it returns nil for an
+	// inaccessible callback message, and never the pinned message.
+	EffectiveMessage() *Message
+
 	// Callback returns stored callback if such presented.
 	Callback() *Callback
 
+	// CallbackState unmarshals the state of the callback button, set
+	// with Btn.WithState, into v. Returns ErrCallbackStateNotFound if
+	// the button has no state, or it's expired or lost on restart.
+	CallbackState(v any) error
+
+	// CallbackUnmarshal unpacks the callback data made by
+	// Bot.MarshalCallback into the struct dest points to.
+	CallbackUnmarshal(dest any) error
+
+	// BusinessConnectionID returns the identifier of the business
+	// connection the update comes from, or an empty string.
+	BusinessConnectionID() string
+
 	// Query returns stored query if such presented.
 	Query() *Query
 
 	// InlineResult returns stored inline result if such presented.
 	InlineResult() *InlineResult
 
+	// InlineMessageID returns the identifier of the inline message
+	// the chosen inline result or the callback comes from.
+	// Returns an empty string if it's not an inline message.
+	InlineMessageID() string
+
 	// ShippingQuery returns stored shipping query if such presented.
 	ShippingQuery() *ShippingQuery
 
@@ -80,6 +138,17 @@ type Context interface {
 	// Chat returns the current chat, depending on the context type.
 	// Returns nil if chat is not presented.
 	Chat() *Chat
+
+	// IsChatAdmin reports whether the user is the creator or an admin
+	// of the current chat, see Bot.CachedAdminsOf. Always false in the
+	// private chats and with no chat.
+	IsChatAdmin(user *User) (bool, error)
+
+	// SenderIsAdmin reports whether the sender is the creator or an admin
+	// of the current chat, see IsChatAdmin. The anonymous admins, posting
+	// on behalf of the chat, are admins as well.
+	SenderIsAdmin() (bool, error)
+
 	// Recipient combines both Sender and Chat functions. If there is no user
 	// the chat will be returned. The native context cannot be without sender,
 	// but it is useful in the case when the context created intentionally
@@ -93,10 +162,45 @@ type Context interface {
 	// ThreadID returns the current message thread ID.
 	ThreadID() int
 
+	// DirectMessageUser returns the user who wrote to the channel
+	// direct messages chat, or nil if the message is not from there.
+	DirectMessageUser() *User
+
 	// Entities returns the message entities, whether it's media caption's or the text's.
 	// In the case when no entities presented, returns a nil.
 	Entities() Entities
 
+	// IsMentioned reports whether the bot is mentioned in the message,
+	// either by its username or by a text mention of its ID.
+	IsMentioned() bool
+
+	// MentionText returns the message text following the bot mention
+	// with the surrounding spaces trimmed. In the case when the bot
+	// isn't mentioned, returns an empty string.
+	MentionText() string
+
+	// Mentions returns the users mentioned in the message, see Message.Mentions.
+	Mentions() []User
+
+	// URLs returns the links in the message, see Message.URLs.
+	URLs() []string
+
+	// Hashtags returns the hashtags in the message, see Message.Hashtags.
+	Hashtags() []string
+
+	// CustomEmoji returns the IDs of the custom emoji in the message.
+	CustomEmoji() []string
+
+	// Matches returns the submatches of the regexp the text matched,
+	// the whole match first, see Bot.HandleRegexp. Returns nil if
+	// the update isn't routed by a regexp.
+	Matches() []string
+
+	// Param returns the parameter of the callback pattern the data
+	// matched, see Bot.HandleCallback. Returns an empty string if
+	// there is no such parameter.
+	Param(name string) string
+
 	// Data returns the current data, depending on the context type.
 	// If the context contains command, returns its arguments string.
 	// If the context contains payment, returns its payload.
@@ -107,28 +211,74 @@ type Context interface {
 	// The message arguments split by space, while the callback's ones by a "|" symbol.
 	Args() []string
 
+	// BindArgs parses Args into the struct dest points to, see BindArgs.
+	BindArgs(dest any) error
+
+	// StartPayload returns the deep-linking payload of the /start command,
+	// e.g. "ref_abc123" for t.me/bot?start=ref_abc123. Returns an empty
+	// string if the message isn't /start. See DecodeStartPayload,
+	// UnmarshalStartPayload and VerifyStartPayload to decode it.
+	StartPayload() string
+
+	// AlbumMessages returns the messages of the current media group
+	// in order of their arrival. It's only populated within the OnAlbum
+	// handler, see Settings.AlbumTimeout.
+	AlbumMessages() []*Message
+
+	// AlbumPhotos returns the photos of the current media group.
+	// It's only populated within the OnAlbum handler.
+	AlbumPhotos() []*Photo
+
+	// AlbumCaption returns the caption of the current media group,
+	// which is stored in one of its messages.
+	// It's only populated within the OnAlbum handler.
+	AlbumCaption() string
+
+	// AlbumMediaGroupID returns the media group identifier.
+	// It's only populated within the OnAlbum handler.
+	AlbumMediaGroupID() string
+
 	// Send sends a message to the current recipient.
 	// See Send from bot.go.
+	//
+	// Within a business message handler, the message is sent on behalf
+	// of the business account, and ErrBusinessCannotReply is returned
+	// if the connection doesn't allow it.
 	Send(what any, opts ...any) error
 
-	// SendAlbum sends an album to the current recipient.
-	// See SendAlbum from bot.go.
-	SendAlbum(a Album, opts ...any) error
+	// SendAlbum sends an album to the current recipient and topic,
+	// replying to the current message if there is one. Returns the
+	// sent messages. See SendAlbum from bot.go.
+	SendAlbum(a Album, opts ...any) ([]Message, error)
 
 	// Reply replies to the current message.
-	// See Reply from bot.go.
+	// See Reply from bot.go. Business messages are replied
+	// the same way Send sends to them.
 	Reply(what any, opts ...any) error
 
+	// ReplyWithQuote replies to the current message quoting the fragment
+	// of its text, see QuoteReply.
+	ReplyWithQuote(what any, quote string, opts ...any) error
+
 	// Forward forwards the given message to the current recipient.
 	// See Forward from bot.go.
 	Forward(msg Editable, opts ...any) error
 
-	// ForwardTo forwards the current message to the given recipient.
-	// See Forward from bot.go
-	ForwardTo(to Recipient, opts ...any) error
+	// ForwardTo forwards the current message to the given recipient and
+	// returns the forwarded message. Pass a *Topic to forward to a topic,
+	// or Protected to protect the contents. See Forward from bot.go.
+	ForwardTo(to Recipient, opts ...any) (*Message, error)
+
+	// CopyTo copies the current message to the given recipient and returns
+	// the copy. Set SendOptions.Caption to override the caption.
+	// See Copy from bot.go.
+	CopyTo(to Recipient, opts ...any) (*Message, error)
 
 	// Edit edits the current message.
 	// See Edit from bot.go.
+	//
+	// Inline messages are edited by their InlineMessageID, and
+	// the True result Telegram returns for them is not an error.
 	Edit(what any, opts ...any) error
 
 	// EditCaption edits the caption of the current message.
@@ -137,10 +287,12 @@ type Context interface {
 
 	// EditOrSend edits the current message if the update is callback,
 	// otherwise the content is sent to the chat as a separate message.
+	// Editing the message to the content it already has is not an error.
 	EditOrSend(what any, opts ...any) error
 
 	// EditOrReply edits the current message if the update is callback,
 	// otherwise the content is replied as a separate message.
+	// Editing the message to the content it already has is not an error.
 	EditOrReply(what any, opts ...any) error
 
 	// Delete removes the current message.
@@ -149,13 +301,41 @@ type Context interface {
 
 	// DeleteAfter waits for the duration to elapse and then removes the
 	// message. It handles an error automatically using b.OnError callback.
-	// It returns a Timer that can be used to cancel the call using its Stop method.
+	// It returns a Timer that can be used to cancel the call using its Stop
+	// method. The deletion is canceled on Bot.Stop as well.
 	DeleteAfter(d time.Duration) *time.Timer
 
+	// SendTemp sends the message to the current recipient and deletes
+	// it once the duration elapses. With the DeleteCommand option,
+	// the current message is deleted along with it:
+	//
+	//	return c.SendTemp("Done!", 10*time.Second, tele.DeleteCommand)
+	SendTemp(what any, d time.Duration, opts ...any) error
+
 	// Notify updates the chat action for the current recipient.
 	// See Notify from bot.go.
 	Notify(action ChatAction) error
 
+	// LongOp starts a scope for a long operation: it keeps sending the chat
+	// action to the current recipient until the timeout expires or done
+	// is called, whichever happens first. The returned context is canceled
+	// at the same moment, or once the bot stops, so use it to bound the work.
+	// Zero timeout means no deadline. Always call done.
+	LongOp(action ChatAction, timeout time.Duration) (ctx context.Context, done func())
+
+	// NotifyDuring keeps sending the chat action to the current recipient
+	// while fn runs, and returns its error, see LongOp:
+	//
+	//	return c.NotifyDuring(tele.UploadingPhoto, func() error {
+	//		return c.Send(render())
+	//	})
+	NotifyDuring(action ChatAction, fn func() error) error
+
+	// DownloadReplyDocument streams the document of the message the current
+	// one replies to into w, see DownloadTo from bot.go. Returns ErrNoDocument
+	// if the replied message has no document.
+	DownloadReplyDocument(w io.Writer, progress ...DownloadProgress) error
+
 	// Ship replies to the current shipping query.
 	// See Ship from bot.go.
 	Ship(what ...any) error
@@ -168,8 +348,16 @@ type Context interface {
 	// See Answer from bot.go.
 	Answer(resp *QueryResponse) error
 
+	// AnswerPaged responds to the current inline query with the page of
+	// the results it asks for by its offset, and sets the next offset,
+	// see Query.Page. The rest of the response is taken from resp:
+	//
+	//	return c.AnswerPaged(results, 20, &tele.QueryResponse{CacheTime: 60})
+	AnswerPaged(results Results, pageSize int, resp ...*QueryResponse) error
+
 	// Respond sends a response for the current callback query.
-	// See Respond from bot.go.
+	// See Respond from bot.go. A callback query is answered only once,
+	// so the calls after the first successful one are no-op.
 	Respond(resp ...*CallbackResponse) error
 
 	// RespondText sends a popup response for the current callback query.
@@ -178,14 +366,67 @@ type Context interface {
 	// RespondAlert sends an alert response for the current callback query.
 	RespondAlert(text string) error
 
-	// Get retrieves data from the context.
+	// RespondURL answers the current callback query with the URL to be
+	// opened by the client, the game URL or a t.me deep link to the bot.
+	RespondURL(url string) error
+
+	// Get retrieves data from the context, nil if the key isn't set.
+	// See ContextValue for the typed access.
 	Get(key string) any
 
-	// Set saves data in the context.
+	// Set saves data in the context. The data is kept for the update
+	// only, so the middleware can pass it to the handlers. It's safe
+	// for concurrent use.
 	Set(key string, val any)
 
 	// Logger returns the logger instance associated with this context.
 	Logger() Logger
+
+	// Defer registers f to run once the handler chain completes, with the
+	// error it returned, ErrSkip if aborted. The functions run in the LIFO
+	// order before the error is passed to OnError, e.g.:
+	//
+	//	c.Defer(func(err error) {
+	//		metrics.Observe(c.Text(), time.Since(start), err)
+	//	})
+	Defer(f func(err error))
+
+	// Session returns the session of the conversation, loaded from
+	// Settings.SessionStore on the first call. The changes are saved
+	// once the handler returns, see Settings.SessionKey.
+	Session() *Session
+
+	// Locale returns the language of the sender, resolved in order from
+	// the locale they chose (see SetLocale), their Telegram language_code
+	// and Settings.DefaultLocale.
+	Locale() string
+
+	// SetLocale persists the locale the sender chose, see Bot.SetUserLocale.
+	// An empty locale removes the choice.
+	SetLocale(locale string) error
+
+	// Ctx returns the context.Context of the update, the bot context by
+	// default. It's cancelled once the bot is stopped or the handler
+	// times out, see Settings.HandlerTimeout. The Bot API calls made
+	// through the Context are cancelled along with it.
+	Ctx() context.Context
+
+	// SetCtx replaces the context.Context of the update, e.g. with the
+	// one carrying a trace span. Derive it from Ctx to keep it cancelled
+	// on Stop and on the handler timeout.
+	SetCtx(ctx context.Context)
+
+	// Abort marks the context as aborted and returns ErrSkip, which
+	// stops the handler chain without being treated as an error:
+	//
+	//	if !allowed(c.Sender()) {
+	//		return c.Abort()
+	//	}
+	Abort() error
+
+	// Aborted reports whether Abort was called, so the middleware
+	// running after the chain can tell an aborted update apart.
+	Aborted() bool
 }
 
 // nativeContext is a native implementation of the Context interface.
@@ -195,12 +436,181 @@ type nativeContext struct {
 	u     Update
 	lock  sync.RWMutex
 	store map[string]any
+	album []*Message
+
+	aborted bool
+
+	// handled is set once a handler is picked for the update,
+	// see Settings.TrackUnhandled.
+	handled bool
+
+	// logger is the update logger, see Settings.CorrelateLogs.
+	logger Logger
+
+	// logName is the name of the handler logger, see Bot.Logger.
+	logName string
+
+	// correlationID outlives the logger renamed by the handler.
+	correlationID string
+
+	// ctx is the update context, see Ctx.
+	ctx context.Context
+
+	// bound is the bot making the requests with ctx.
+	bound *Bot
+
+	// session is the session loaded by Session.
+	session *Session
+
+	// deferred are the functions registered with Defer.
+	deferred []func(error)
+
+	// locale is the locale resolved by Locale.
+	locale string
+
+	// responded is set once the callback query is answered.
+	responded bool
+
+	// matches are the submatches of the regexp route, see Matches.
+	matches []string
+
+	// params are the parameters of the callback pattern, see Param.
+	params map[string]string
+
+	// onError is the error handler of the group handling the update.
+	onError func(error, Context)
+}
+
+func (c *nativeContext) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.b = nil
+	c.u = Update{}
+	c.album = nil
+	c.aborted = false
+	c.handled = false
+	c.logger = nil
+	c.logName = ""
+	c.correlationID = ""
+	c.ctx = nil
+	c.bound = nil
+	c.session = nil
+	c.deferred = nil
+	c.locale = ""
+	c.responded = false
+	c.matches = nil
+	c.params = nil
+	c.onError = nil
+	clear(c.store)
 }
 
 func (c *nativeContext) Bot() API {
 	return c.b
 }
 
+func (c *nativeContext) Locale() string {
+	c.lock.RLock()
+	locale := c.locale
+	c.lock.RUnlock()
+
+	if locale != "" {
+		return locale
+	}
+
+	bot, _ := c.b.(*Bot)
+	sender := c.Sender()
+	if bot != nil && sender != nil {
+		var err error
+		if locale, err = bot.UserLocale(sender); err != nil {
+			bot.OnError(err, c)
+		}
+	}
+	if locale == "" && sender != nil {
+		locale = sender.LanguageCode
+	}
+	if locale == "" && bot != nil {
+		locale = bot.defaultLocale
+	}
+
+	c.lock.Lock()
+	c.locale = locale
+	c.lock.Unlock()
+	return locale
+}
+
+func (c *nativeContext) SetLocale(locale string) error {
+	bot, ok := c.b.(*Bot)
+	sender := c.Sender()
+	if !ok || sender == nil {
+		return ErrBadContext
+	}
+	if err := bot.SetUserLocale(sender, locale); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.locale = ""
+	c.lock.Unlock()
+	return nil
+}
+
+func (c *nativeContext) Ctx() context.Context {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.ctx != nil {
+		return c.ctx
+	}
+	if bot, ok := c.b.(*Bot); ok {
+		return bot.rootCtx
+	}
+	return context.Background()
+}
+
+func (c *nativeContext) SetCtx(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ctx = ctx
+	c.bound = nil
+	c.logger = nil
+}
+
+// api returns the bot making the requests with the update context.
+func (c *nativeContext) api() API {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	bot, ok := c.b.(*Bot)
+	if !ok || c.ctx == nil {
+		return c.b
+	}
+	if c.bound == nil {
+		c.bound = bot.withContext(c.ctx)
+	}
+	return c.bound
+}
+
+func (c *nativeContext) Chatter() Chatter {
+	m := c.Message()
+	switch {
+	case m != nil:
+	case c.u.BusinessMessage != nil:
+		m = c.u.BusinessMessage
+	case c.u.EditedBusinessMessage != nil:
+		m = c.u.EditedBusinessMessage
+	}
+
+	ch := Chatter{b: c.api(), msg: m}
+	if m != nil && m.Chat != nil {
+		ch.to = m.Chat
+	} else if chat := c.Chat(); chat != nil {
+		ch.to = chat
+	}
+	return ch
+}
+
 func (c *nativeContext) Update() Update {
 	return c.u
 }
@@ -225,14 +635,108 @@ func (c *nativeContext) Message() *Message {
 	}
 }
 
+func (c *nativeContext) EffectiveMessage() *Message {
+	switch {
+	case c.u.Message != nil:
+		return c.u.Message
+	case c.u.EditedMessage != nil:
+		return c.u.EditedMessage
+	case c.u.ChannelPost != nil:
+		return c.u.ChannelPost
+	case c.u.EditedChannelPost != nil:
+		return c.u.EditedChannelPost
+	case c.u.BusinessMessage != nil:
+		return c.u.BusinessMessage
+	case c.u.EditedBusinessMessage != nil:
+		return c.u.EditedBusinessMessage
+	case c.u.Callback != nil:
+		if m := c.u.Callback.Message; m != nil && !m.Inaccessible() {
+			return m
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// businessMessage returns the new or edited business message, if any.
+func (c *nativeContext) businessMessage() *Message {
+	switch {
+	case c.u.BusinessMessage != nil:
+		return c.u.BusinessMessage
+	case c.u.EditedBusinessMessage != nil:
+		return c.u.EditedBusinessMessage
+	default:
+		return nil
+	}
+}
+
+func (c *nativeContext) checkBusinessReply(m *Message) error {
+	if bot, ok := c.b.(*Bot); ok {
+		return bot.checkBusinessReply(m.BusinessConnectionID)
+	}
+	return nil
+}
+
+func (c *nativeContext) BusinessConnectionID() string {
+	switch {
+	case c.u.BusinessConnection != nil:
+		return c.u.BusinessConnection.ID
+	case c.u.DeletedBusinessMessages != nil:
+		return c.u.DeletedBusinessMessages.BusinessConnectionID
+	}
+	if m := c.businessMessage(); m != nil {
+		return m.BusinessConnectionID
+	}
+	return ""
+}
+
 func (c *nativeContext) Callback() *Callback {
 	return c.u.Callback
 }
 
+func (c *nativeContext) CallbackState(v any) error {
+	bot, ok := c.b.(*Bot)
+	if !ok || c.u.Callback == nil || c.u.Callback.stateToken == "" {
+		return ErrCallbackStateNotFound
+	}
+
+	data, err := bot.callbackStore().Get(c.u.Callback.stateToken)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (c *nativeContext) CallbackUnmarshal(dest any) error {
+	cb := c.u.Callback
+	if cb == nil {
+		return ErrCallbackStateNotFound
+	}
+	if cb.stateToken != "" {
+		return c.CallbackState(dest)
+	}
+	return unpackCallback(cb.Data, dest)
+}
+
 func (c *nativeContext) Query() *Query {
 	return c.u.Query
 }
 
+func (c *nativeContext) InlineMessageID() string {
+	switch {
+	case c.u.InlineResult != nil:
+		return c.u.InlineResult.MessageID
+	case c.u.Callback != nil:
+		return c.u.Callback.MessageID
+	default:
+		return ""
+	}
+}
+
 func (c *nativeContext) InlineResult() *InlineResult {
 	return c.u.InlineResult
 }
@@ -356,6 +860,30 @@ func (c *nativeContext) Chat() *Chat {
 	}
 }
 
+func (c *nativeContext) IsChatAdmin(user *User) (bool, error) {
+	chat := c.Chat()
+	if chat == nil || chat.Type == ChatPrivate || user == nil {
+		return false, nil
+	}
+
+	api := c.api()
+	if bot, ok := api.(*Bot); ok {
+		return bot.IsChatAdmin(chat, user)
+	}
+	admins, err := api.AdminsOf(chat)
+	if err != nil {
+		return false, err
+	}
+	return hasAdmin(admins, user), nil
+}
+
+func (c *nativeContext) SenderIsAdmin() (bool, error) {
+	if m := c.Message(); m != nil && m.SenderChat != nil && m.Chat != nil && m.SenderChat.ID == m.Chat.ID {
+		return true, nil
+	}
+	return c.IsChatAdmin(c.Sender())
+}
+
 func (c *nativeContext) Recipient() Recipient {
 	chat := c.Chat()
 	if chat != nil {
@@ -386,6 +914,121 @@ func (c *nativeContext) Entities() Entities {
 	return m.Entities
 }
 
+func (c *nativeContext) Mentions() []User {
+	if m := c.Message(); m != nil {
+		return m.Mentions()
+	}
+	return nil
+}
+
+func (c *nativeContext) URLs() []string {
+	if m := c.Message(); m != nil {
+		return m.URLs()
+	}
+	return nil
+}
+
+func (c *nativeContext) Hashtags() []string {
+	if m := c.Message(); m != nil {
+		return m.Hashtags()
+	}
+	return nil
+}
+
+func (c *nativeContext) CustomEmoji() []string {
+	if m := c.Message(); m != nil {
+		return m.CustomEmoji()
+	}
+	return nil
+}
+
+// mention returns the first entity mentioning the bot.
+func (c *nativeContext) mention() (*Message, MessageEntity, bool) {
+	m := c.Message()
+	bot, ok := c.b.(*Bot)
+	if m == nil || !ok {
+		return nil, MessageEntity{}, false
+	}
+
+	me := bot.Identity()
+	if me == nil {
+		return nil, MessageEntity{}, false
+	}
+	for _, e := range c.Entities() {
+		switch e.Type {
+		case EntityMention:
+			if me.Username != "" && strings.EqualFold(m.EntityText(e), "@"+me.Username) {
+				return m, e, true
+			}
+		case EntityTMention:
+			if me.ID != 0 && e.User != nil && e.User.ID == me.ID {
+				return m, e, true
+			}
+		}
+	}
+	return nil, MessageEntity{}, false
+}
+
+func (c *nativeContext) IsMentioned() bool {
+	_, _, ok := c.mention()
+	return ok
+}
+
+func (c *nativeContext) MentionText() string {
+	m, e, ok := c.mention()
+	if !ok {
+		return ""
+	}
+
+	text := m.Text
+	if text == "" {
+		text = m.Caption
+	}
+
+	a := utf16.Encode([]rune(text))
+	end := e.Offset + e.Length
+	if end > len(a) {
+		return ""
+	}
+	return strings.TrimSpace(string(utf16.Decode(a[end:])))
+}
+
+func (c *nativeContext) Matches() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.matches
+}
+
+func (c *nativeContext) setMatches(matches []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.matches = matches
+}
+
+func (c *nativeContext) Param(name string) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.params[name]
+}
+
+func (c *nativeContext) setParams(params map[string]string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.params = params
+}
+
+func (c *nativeContext) errorHandler() func(error, Context) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.onError
+}
+
+func (c *nativeContext) setOnError(onError func(error, Context)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onError = onError
+}
+
 func (c *nativeContext) Data() string {
 	switch {
 	case c.u.Message != nil:
@@ -409,6 +1052,10 @@ func (c *nativeContext) Data() string {
 	}
 }
 
+func (c *nativeContext) StartPayload() string {
+	return startPayload(c.u.Message)
+}
+
 func (c *nativeContext) Args() []string {
 	m := c.u.Message
 	switch {
@@ -429,6 +1076,10 @@ func (c *nativeContext) Args() []string {
 	return nil
 }
 
+func (c *nativeContext) BindArgs(dest any) error {
+	return BindArgs(c.Args(), dest)
+}
+
 func (c *nativeContext) ThreadID() int {
 	switch {
 	case c.Message() != nil:
@@ -438,13 +1089,68 @@ func (c *nativeContext) ThreadID() int {
 	}
 }
 
+func (c *nativeContext) DirectMessageUser() *User {
+	m := c.Message()
+	if m == nil || m.Chat == nil || !m.Chat.IsDirectMessages() {
+		return nil
+	}
+	if t := m.DirectMessagesTopic; t != nil && t.User != nil {
+		return t.User
+	}
+	return m.Sender
+}
+
+func (c *nativeContext) AlbumMessages() []*Message {
+	return c.album
+}
+
+func (c *nativeContext) AlbumPhotos() []*Photo {
+	var photos []*Photo
+	for _, m := range c.album {
+		if m.Photo != nil {
+			photos = append(photos, m.Photo)
+		}
+	}
+	return photos
+}
+
+func (c *nativeContext) AlbumCaption() string {
+	for _, m := range c.album {
+		if m.Caption != "" {
+			return m.Caption
+		}
+	}
+	return ""
+}
+
+func (c *nativeContext) AlbumMediaGroupID() string {
+	if len(c.album) == 0 {
+		return ""
+	}
+	return c.album[0].AlbumID
+}
+
 func (c *nativeContext) Send(what any, opts ...any) error {
+	if m := c.businessMessage(); m != nil {
+		if err := c.checkBusinessReply(m); err != nil {
+			return err
+		}
+		_, err := c.Chatter().Send(what, opts...)
+		return err
+	}
+
 	opts = c.inheritOpts(opts...)
-	_, err := c.b.Send(c.Recipient(), what, opts...)
+	_, err := c.api().Send(c.Recipient(), what, opts...)
 	return err
 }
 
 func (c *nativeContext) inheritOpts(opts ...any) []any {
+	return inheritOpts(c.Message(), opts)
+}
+
+// inheritOpts appends the options needed to respond to the message
+// in its thread or direct messages topic.
+func inheritOpts(m *Message, opts []any) []any {
 	var (
 		ignoreThread bool
 	)
@@ -464,69 +1170,118 @@ func (c *nativeContext) inheritOpts(opts ...any) []any {
 		}
 	}
 
+	if m == nil {
+		return opts
+	}
+
 	switch {
-	case !ignoreThread && c.ThreadID() != 0 && c.Message().TopicMessage:
-		opts = append(opts, &Topic{ThreadID: c.ThreadID()})
+	case !ignoreThread && m.ThreadID != 0 && m.TopicMessage:
+		opts = append(opts, &Topic{ThreadID: m.ThreadID})
+	}
+
+	if m.DirectMessagesTopic != nil {
+		opts = append(opts, m.DirectMessagesTopic)
 	}
 
 	return opts
 }
 
-func (c *nativeContext) SendAlbum(a Album, opts ...any) error {
+func (c *nativeContext) SendAlbum(a Album, opts ...any) ([]Message, error) {
+	// The reply to the incoming message goes first,
+	// so that it can be overridden by the given options.
+	if m := c.u.Message; m != nil {
+		opts = append([]any{&ReplyParams{MessageID: m.ID}}, opts...)
+	}
 	opts = c.inheritOpts(opts...)
 
-	_, err := c.b.SendAlbum(c.Recipient(), a, opts...)
-	return err
+	return c.api().SendAlbum(c.Recipient(), a, opts...)
 }
 
 func (c *nativeContext) Reply(what any, opts ...any) error {
+	if m := c.businessMessage(); m != nil {
+		if err := c.checkBusinessReply(m); err != nil {
+			return err
+		}
+		_, err := c.Chatter().Reply(what, opts...)
+		return err
+	}
+
 	msg := c.Message()
 	if msg == nil {
 		return ErrBadContext
 	}
 	opts = c.inheritOpts(opts...)
-	_, err := c.b.Reply(msg, what, opts...)
+	_, err := c.api().Reply(msg, what, opts...)
 	return err
 }
 
+func (c *nativeContext) ReplyWithQuote(what any, quote string, opts ...any) error {
+	msg := c.Message()
+	if msg == nil {
+		return ErrBadContext
+	}
+
+	params, err := QuoteReply(msg, quote)
+	if err != nil {
+		return err
+	}
+	return c.Send(what, append([]any{params}, opts...)...)
+}
+
 func (c *nativeContext) Forward(msg Editable, opts ...any) error {
-	_, err := c.b.Forward(c.Recipient(), msg, opts...)
+	_, err := c.api().Forward(c.Recipient(), msg, opts...)
 	return err
 }
 
-func (c *nativeContext) ForwardTo(to Recipient, opts ...any) error {
+func (c *nativeContext) ForwardTo(to Recipient, opts ...any) (*Message, error) {
 	msg := c.Message()
 	if msg == nil {
-		return ErrBadContext
+		return nil, ErrBadContext
 	}
-	_, err := c.b.Forward(to, msg, opts...)
-	return err
+	return c.api().Forward(to, msg, opts...)
+}
+
+func (c *nativeContext) CopyTo(to Recipient, opts ...any) (*Message, error) {
+	msg := c.Message()
+	if msg == nil {
+		return nil, ErrBadContext
+	}
+	return c.api().Copy(to, msg, opts...)
 }
 
 func (c *nativeContext) Edit(what any, opts ...any) error {
 	opts = c.inheritOpts(opts...)
 
 	if c.u.InlineResult != nil {
-		_, err := c.b.Edit(c.u.InlineResult, what, opts...)
-		return err
+		_, err := c.api().Edit(c.u.InlineResult, what, opts...)
+		return editResult(err)
 	}
 	if c.u.Callback != nil {
-		_, err := c.b.Edit(c.u.Callback, what, opts...)
-		return err
+		_, err := c.api().Edit(c.u.Callback, what, opts...)
+		return editResult(err)
 	}
 	return ErrBadContext
 }
 
+// editResult drops ErrTrueResult, which is returned on successful
+// edits of the inline messages, as there is no message to return.
+func editResult(err error) error {
+	if err == ErrTrueResult {
+		return nil
+	}
+	return err
+}
+
 func (c *nativeContext) EditCaption(caption string, opts ...any) error {
 	opts = c.inheritOpts(opts...)
 
 	if c.u.InlineResult != nil {
-		_, err := c.b.EditCaption(c.u.InlineResult, caption, opts...)
-		return err
+		_, err := c.api().EditCaption(c.u.InlineResult, caption, opts...)
+		return editResult(err)
 	}
 	if c.u.Callback != nil {
-		_, err := c.b.EditCaption(c.u.Callback, caption, opts...)
-		return err
+		_, err := c.api().EditCaption(c.u.Callback, caption, opts...)
+		return editResult(err)
 	}
 	return ErrBadContext
 }
@@ -536,7 +1291,7 @@ func (c *nativeContext) EditOrSend(what any, opts ...any) error {
 	if err == ErrBadContext {
 		return c.Send(what, opts...)
 	}
-	return err
+	return ignoreNotModified(err)
 }
 
 func (c *nativeContext) EditOrReply(what any, opts ...any) error {
@@ -544,6 +1299,15 @@ func (c *nativeContext) EditOrReply(what any, opts ...any) error {
 	if err == ErrBadContext {
 		return c.Reply(what, opts...)
 	}
+	return ignoreNotModified(err)
+}
+
+// ignoreNotModified drops the error Telegram returns when the message
+// is edited to the same content, e.g. when a button is pressed twice.
+func ignoreNotModified(err error) error {
+	if errors.Is(err, ErrMessageNotModified) || errors.Is(err, ErrSameMessageContent) {
+		return nil
+	}
 	return err
 }
 
@@ -552,42 +1316,178 @@ func (c *nativeContext) Delete() error {
 	if msg == nil {
 		return ErrBadContext
 	}
-	return c.b.Delete(msg)
+	return c.api().Delete(msg)
 }
 
 func (c *nativeContext) DeleteAfter(d time.Duration) *time.Timer {
+	// Capture everything needed upfront, the context itself
+	// may be already reused by the time the timer fires.
+	api, msg := c.b, c.Message()
+	stopped := context.Background()
+	if b, ok := api.(*Bot); ok {
+		stopped = b.rootCtx
+	}
+
 	return time.AfterFunc(d, func() {
-		if err := c.Delete(); err != nil {
-			if b, ok := c.b.(*Bot); ok {
-				b.OnError(err, c)
+		if stopped.Err() != nil {
+			return
+		}
+
+		err := ErrBadContext
+		if msg != nil {
+			err = api.Delete(msg)
+		}
+		if err != nil {
+			if b, ok := api.(*Bot); ok {
+				b.OnError(err, nil)
 			}
 		}
 	})
 }
 
+func (c *nativeContext) SendTemp(what any, d time.Duration, opts ...any) error {
+	b, ok := c.b.(*Bot)
+	if !ok {
+		return ErrBadContext
+	}
+
+	var deleteCommand bool
+	for _, opt := range opts {
+		if opt == DeleteCommand {
+			deleteCommand = true
+		}
+	}
+
+	var (
+		msg *Message
+		err error
+	)
+	if m := c.businessMessage(); m != nil {
+		if err := c.checkBusinessReply(m); err != nil {
+			return err
+		}
+		msg, err = c.Chatter().Send(what, opts...)
+	} else {
+		msg, err = c.api().Send(c.Recipient(), what, c.inheritOpts(opts...)...)
+	}
+	if err != nil {
+		return err
+	}
+
+	b.DeleteAfter(msg, d)
+	if deleteCommand && c.Message() != nil {
+		b.DeleteAfter(c.Message(), d)
+	}
+	return nil
+}
+
 func (c *nativeContext) Notify(action ChatAction) error {
-	return c.b.Notify(c.Recipient(), action, c.ThreadID())
+	return c.api().Notify(c.Recipient(), action, c.ThreadID())
+}
+
+// longOpInterval is how often the chat action is repeated during
+// LongOp, Telegram shows it for 5 seconds or less.
+const longOpInterval = 4 * time.Second
+
+func (c *nativeContext) NotifyDuring(action ChatAction, fn func() error) error {
+	_, done := c.LongOp(action, 0)
+	defer done()
+	return fn()
+}
+
+func (c *nativeContext) DownloadReplyDocument(w io.Writer, progress ...DownloadProgress) error {
+	m := c.Message()
+	if m == nil || m.ReplyTo == nil || m.ReplyTo.Document == nil {
+		return ErrNoDocument
+	}
+	return c.api().DownloadTo(&m.ReplyTo.Document.File, w, progress...)
+}
+
+func (c *nativeContext) LongOp(action ChatAction, timeout time.Duration) (context.Context, func()) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(c.Ctx(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(c.Ctx())
+	}
+
+	// As in DeleteAfter, the context may be reused while the
+	// operation is still running.
+	api, to, threadID := c.api(), c.Recipient(), c.ThreadID()
+	notify := func() {
+		if err := api.Notify(to, action, threadID); err != nil {
+			if b, ok := api.(*Bot); ok {
+				b.OnError(err, nil)
+			}
+		}
+	}
+
+	var clock Clock = realClock{}
+	if b, ok := api.(*Bot); ok {
+		clock = b.clock
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		timer := clock.NewTimer(longOpInterval)
+		defer timer.Stop()
+
+		for notify(); ; {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				notify()
+				timer.Reset(longOpInterval)
+			}
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		<-stopped
+	}
 }
 
 func (c *nativeContext) Ship(what ...any) error {
 	if c.u.ShippingQuery == nil {
 		return errors.New("telebot: context shipping query is nil")
 	}
-	return c.b.Ship(c.u.ShippingQuery, what...)
+	return c.api().Ship(c.u.ShippingQuery, what...)
 }
 
 func (c *nativeContext) Accept(errorMessage ...string) error {
 	if c.u.PreCheckoutQuery == nil {
 		return errors.New("telebot: context pre checkout query is nil")
 	}
-	return c.b.Accept(c.u.PreCheckoutQuery, errorMessage...)
+	return c.api().Accept(c.u.PreCheckoutQuery, errorMessage...)
 }
 
 func (c *nativeContext) Respond(resp ...*CallbackResponse) error {
 	if c.u.Callback == nil {
 		return errors.New("telebot: context callback is nil")
 	}
-	return c.b.Respond(c.u.Callback, resp...)
+
+	c.lock.RLock()
+	responded := c.responded
+	c.lock.RUnlock()
+	if responded {
+		return nil
+	}
+
+	if err := c.api().Respond(c.u.Callback, resp...); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.responded = true
+	c.lock.Unlock()
+	return nil
 }
 
 func (c *nativeContext) RespondText(text string) error {
@@ -598,11 +1498,28 @@ func (c *nativeContext) RespondAlert(text string) error {
 	return c.Respond(&CallbackResponse{Text: text, ShowAlert: true})
 }
 
+func (c *nativeContext) RespondURL(url string) error {
+	return c.Respond(&CallbackResponse{URL: url})
+}
+
 func (c *nativeContext) Answer(resp *QueryResponse) error {
 	if c.u.Query == nil {
 		return errors.New("telebot: context inline query is nil")
 	}
-	return c.b.Answer(c.u.Query, resp)
+	return c.api().Answer(c.u.Query, resp)
+}
+
+func (c *nativeContext) AnswerPaged(results Results, pageSize int, resp ...*QueryResponse) error {
+	if c.u.Query == nil {
+		return errors.New("telebot: context inline query is nil")
+	}
+
+	var r QueryResponse
+	if len(resp) > 0 && resp[0] != nil {
+		r = *resp[0]
+	}
+	r.Results, r.NextOffset = c.u.Query.Page(results, pageSize)
+	return c.api().Answer(c.u.Query, &r)
 }
 
 func (c *nativeContext) Set(key string, value any) {
@@ -622,10 +1539,146 @@ func (c *nativeContext) Get(key string) any {
 	return c.store[key]
 }
 
+// ContextValue returns the data saved in the context by the key,
+// if it's set and has the type T:
+//
+//	user, ok := tele.ContextValue[*User](c, "user")
+func ContextValue[T any](c Context, key string) (T, bool) {
+	v, ok := c.Get(key).(T)
+	return v, ok
+}
+
+func (c *nativeContext) Abort() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.aborted = true
+	return ErrSkip
+}
+
+func (c *nativeContext) Aborted() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.aborted
+}
+
 func (c *nativeContext) Logger() Logger {
+	bot, ok := c.b.(*Bot)
+	if !ok {
+		// Fallback to no-op logger if bot is not available
+		return NewNoOpLogger()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// The same logger is kept for the whole update, so the
+	// middleware and the handlers share the correlation ID.
+	if c.logger == nil {
+		c.logger = bot.logger
+		if c.logName != "" {
+			c.logger = bot.Logger(c.logName)
+		}
+		if bot.correlateLogs {
+			c.logger = LoggerWith(c.logger, c.logFields()...)
+		}
+		c.logger = LoggerWithContext(c.logger, c.ctx)
+	}
+	return c.logger
+}
+
+func (c *nativeContext) Session() *Session {
+	c.lock.RLock()
+	s := c.session
+	c.lock.RUnlock()
+
+	if s != nil {
+		return s
+	}
+
+	// The session is loaded with the context unlocked,
+	// as the key func may use it.
+	s = newSession("")
 	if bot, ok := c.b.(*Bot); ok {
-		return bot.logger
+		keyFunc := bot.sessionKey
+		if keyFunc == nil {
+			keyFunc = DefaultSessionKey
+		}
+		if key := keyFunc(c); key != "" {
+			s = newSession(key)
+			if err := s.load(bot.sessionStore()); err != nil {
+				bot.OnError(err, c)
+				// Don't overwrite the session which failed to load.
+				s = newSession("")
+			}
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.session != nil {
+		return c.session
+	}
+	c.session = s
+
+	// The changes are saved once the handler chain completes.
+	if bot, ok := c.b.(*Bot); ok {
+		c.deferred = append(c.deferred, func(error) {
+			if err := s.save(bot.sessionStore(), bot.sessionTTL); err != nil {
+				bot.OnError(err, c)
+			}
+		})
+	}
+	return s
+}
+
+func (c *nativeContext) Defer(f func(err error)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deferred = append(c.deferred, f)
+}
+
+// runDeferred runs the functions registered with Defer in the LIFO order.
+func (c *nativeContext) runDeferred(err error) {
+	c.lock.Lock()
+	deferred := c.deferred
+	c.deferred = nil
+	c.lock.Unlock()
+
+	for i := len(deferred) - 1; i >= 0; i-- {
+		deferred[i](err)
+	}
+}
+
+// setLogName names the context logger after the handler.
+func (c *nativeContext) setLogName(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.logName != name {
+		c.logName = name
+		c.logger = nil
+	}
+}
+
+// logFields returns the key-value pairs identifying the update
+// in the logs, see Settings.CorrelateLogs.
+func (c *nativeContext) logFields() []any {
+	if c.correlationID == "" {
+		var raw [8]byte
+		rand.Read(raw[:])
+		c.correlationID = hex.EncodeToString(raw[:])
+	}
+
+	fields := []any{
+		"correlation_id", c.correlationID,
+		"update_id", c.u.ID,
+	}
+	if chat := c.Chat(); chat != nil {
+		fields = append(fields, "chat_id", chat.ID)
+	}
+	if sender := c.Sender(); sender != nil {
+		fields = append(fields, "sender_id", sender.ID)
 	}
-	// Fallback to no-op logger if bot is not available
-	return NewNoOpLogger()
+	return fields
 }
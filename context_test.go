@@ -1,9 +1,19 @@
 package telebot
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var _ Context = (*nativeContext)(nil)
@@ -13,5 +23,547 @@ func TestContext(t *testing.T) {
 		c := new(nativeContext)
 		c.Set("name", "Jon Snow")
 		assert.Equal(t, "Jon Snow", c.Get("name"))
+
+		name, ok := ContextValue[string](c, "name")
+		assert.True(t, ok)
+		assert.Equal(t, "Jon Snow", name)
+
+		_, ok = ContextValue[int](c, "name")
+		assert.False(t, ok)
+		_, ok = ContextValue[string](c, "missing")
+		assert.False(t, ok)
+
+		c.reset()
+		assert.Nil(t, c.Get("name"))
+	})
+
+	t.Run("Album", func(t *testing.T) {
+		b, err := NewBot(Settings{Synchronous: true, Offline: true, AlbumTimeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+
+		done := make(chan Context, 1)
+		b.Handle(OnAlbum, func(c Context) error {
+			done <- c
+			return nil
+		})
+		b.Handle(OnPhoto, func(c Context) error {
+			t.Fatal("album photo must not be handled separately")
+			return nil
+		})
+
+		b.ProcessUpdate(Update{ID: 1, Message: &Message{ID: 1, AlbumID: "42", Photo: &Photo{}}})
+		b.ProcessUpdate(Update{ID: 2, Message: &Message{ID: 2, AlbumID: "42", Video: &Video{}, Caption: "caption"}})
+		b.ProcessUpdate(Update{ID: 3, Message: &Message{ID: 3, AlbumID: "42", Photo: &Photo{}}})
+
+		c := <-done
+		require.Len(t, c.AlbumMessages(), 3)
+		assert.Equal(t, 1, c.AlbumMessages()[0].ID)
+		assert.Equal(t, 3, c.AlbumMessages()[2].ID)
+		assert.Len(t, c.AlbumPhotos(), 2)
+		assert.Equal(t, "caption", c.AlbumCaption())
+		assert.Equal(t, "42", c.AlbumMediaGroupID())
+		assert.Equal(t, 1, c.Update().ID)
+	})
+	t.Run("DirectMessages", func(t *testing.T) {
+		b, err := NewBot(Settings{Synchronous: true, Offline: true})
+		require.NoError(t, err)
+
+		user := &User{ID: 1}
+		topic := &DirectMessagesTopic{TopicID: 10, User: user}
+		chat := &Chat{ID: -100, Type: ChatSuperGroup, DirectMessages: true}
+
+		var got *User
+		b.Handle(OnDirectMessage, func(c Context) error {
+			got = c.DirectMessageUser()
+			return nil
+		})
+		b.Handle(OnText, func(c Context) error {
+			t.Fatal("direct message must be handled by OnDirectMessage")
+			return nil
+		})
+
+		b.ProcessUpdate(Update{Message: &Message{
+			Text:                "hello",
+			Sender:              user,
+			Chat:                chat,
+			DirectMessagesTopic: topic,
+		}})
+		assert.Equal(t, user, got)
+
+		c := b.NewContext(Update{Message: &Message{Chat: chat, DirectMessagesTopic: topic}}).(*nativeContext)
+		opts := b.extractOptions(c.inheritOpts())
+		assert.Equal(t, int64(10), opts.DirectMessagesTopicID)
+
+		c = b.NewContext(Update{Message: &Message{Sender: user, Chat: &Chat{Type: ChatPrivate}}}).(*nativeContext)
+		assert.Nil(t, c.DirectMessageUser())
+	})
+	t.Run("SendAlbum", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":[{"message_id":2},{"message_id":3}]}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Message: &Message{
+			ID:           1,
+			ThreadID:     5,
+			TopicMessage: true,
+			Chat:         &Chat{ID: 42},
+		}})
+
+		photo := &Photo{File: File{FileID: "photo"}}
+		msgs, err := c.SendAlbum(Album{photo, photo})
+		require.NoError(t, err)
+		assert.Len(t, msgs, 2)
+		assert.Equal(t, "42", params["chat_id"])
+		assert.Equal(t, "5", params["message_thread_id"])
+		assert.JSONEq(t, `{"message_id":1}`, params["reply_parameters"])
+
+		_, err = c.SendAlbum(Album{photo})
+		assert.Error(t, err)
+
+		_, err = c.SendAlbum(Album{photo, &Document{File: File{FileID: "doc"}}})
+		assert.Error(t, err)
+	})
+	t.Run("LongOp", func(t *testing.T) {
+		actions := make(chan map[string]string, 10)
+		srv := newTestAPI(t, func(call apiCall) string {
+			actions <- call.Params
+			return `{"ok":true,"result":true}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Message: &Message{Chat: &Chat{ID: 42}}})
+
+		ctx, done := c.LongOp(Typing, 50*time.Millisecond)
+		params := <-actions
+		assert.Equal(t, "42", params["chat_id"])
+		assert.Equal(t, string(Typing), params["action"])
+
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		done()
+
+		ctx, done = c.LongOp(UploadingPhoto, 0)
+		<-actions
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		done()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+		failed := errors.New("failed")
+		err = c.NotifyDuring(RecordingVideo, func() error {
+			params := <-actions
+			assert.Equal(t, string(RecordingVideo), params["action"])
+			return failed
+		})
+		assert.Equal(t, failed, err)
+	})
+	t.Run("Chatter", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":{"message_id":2}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{BusinessMessage: &Message{
+			ID:                   1,
+			ThreadID:             5,
+			TopicMessage:         true,
+			Chat:                 &Chat{ID: 42},
+			BusinessConnectionID: "conn",
+		}})
+
+		ch := c.Chatter()
+		_, err = ch.Send("text")
+		require.NoError(t, err)
+		assert.Equal(t, "42", params["chat_id"])
+		assert.Equal(t, "5", params["message_thread_id"])
+		assert.Equal(t, "conn", params["business_connection_id"])
+
+		_, err = ch.Reply("text", IgnoreThread)
+		require.NoError(t, err)
+		assert.Equal(t, "1", params["reply_to_message_id"])
+		assert.Empty(t, params["message_thread_id"])
+
+		require.NoError(t, ch.Action(Typing))
+		assert.Equal(t, "conn", params["business_connection_id"])
+
+		_, err = b.NewContext(Update{}).Chatter().Send("text")
+		assert.Equal(t, ErrBadRecipient, err)
+
+		allocs := testing.AllocsPerRun(10, func() { _ = c.Chatter() })
+		assert.Zero(t, allocs)
+	})
+	t.Run("InlineEdit", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":true}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Callback: &Callback{ID: "1", MessageID: "inline"}})
+		assert.Equal(t, "inline", c.InlineMessageID())
+
+		for _, what := range []any{"text", &ReplyMarkup{}, &Photo{File: File{FileID: "photo"}}, Location{Lat: 1, Lng: 2}} {
+			require.NoError(t, c.Edit(what))
+			assert.Equal(t, "inline", params["inline_message_id"])
+			assert.NotContains(t, params, "chat_id")
+		}
+		require.NoError(t, c.EditCaption("caption"))
+
+		c = b.NewContext(Update{InlineResult: &InlineResult{MessageID: "result"}})
+		assert.Equal(t, "result", c.InlineMessageID())
+
+		_, err = b.StopLiveLocation(c.InlineResult())
+		assert.Equal(t, ErrTrueResult, err)
+		assert.Equal(t, "result", params["inline_message_id"])
+
+		err = c.Edit(&Photo{File: FromReader(strings.NewReader("photo"))})
+		assert.ErrorContains(t, err, "inline message")
+
+		assert.Empty(t, b.NewContext(Update{Message: &Message{}}).InlineMessageID())
+	})
+	t.Run("EditOrSend", func(t *testing.T) {
+		var methods []string
+		srv := newTestAPI(t, func(call apiCall) string {
+			methods = append(methods, call.Method)
+			if call.Method == "editMessageText" {
+				return `{"ok":false,"error_code":400,"description":"Bad Request: message is not modified"}`
+			}
+			return `{"ok":true,"result":{"message_id":2}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		msg := &Message{ID: 1, Chat: &Chat{ID: 42}}
+		c := b.NewContext(Update{Callback: &Callback{Message: msg}})
+		assert.NoError(t, c.EditOrSend("same"))
+		assert.NoError(t, c.EditOrReply("same"))
+
+		c = b.NewContext(Update{Message: msg})
+		assert.NoError(t, c.EditOrSend("new"))
+		assert.NoError(t, c.EditOrReply("new"))
+
+		assert.Equal(t, []string{"editMessageText", "editMessageText", "sendMessage", "sendMessage"}, methods)
+	})
+	t.Run("Respond", func(t *testing.T) {
+		var answers []map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			answers = append(answers, call.Params)
+			return `{"ok":true,"result":true}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Callback: &Callback{ID: "1"}})
+		require.NoError(t, c.RespondURL("https://t.me/bot?start=game"))
+		require.NoError(t, c.RespondAlert("ignored"))
+		require.NoError(t, c.Respond())
+
+		c = b.NewContext(Update{Callback: &Callback{ID: "2"}})
+		require.NoError(t, c.RespondAlert("Denied"))
+
+		require.Len(t, answers, 2)
+		assert.Equal(t, "https://t.me/bot?start=game", answers[0]["url"])
+		assert.Equal(t, map[string]string{"callback_query_id": "2", "text": "Denied", "show_alert": "true"}, answers[1])
 	})
+	t.Run("ForwardTo,CopyTo", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":{"message_id":5,"chat":{"id":7}}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Message: &Message{ID: 1, Chat: &Chat{ID: 42}, Caption: "old"}})
+		msg, err := c.ForwardTo(&Chat{ID: 7}, &Topic{ThreadID: 3}, Protected)
+		require.NoError(t, err)
+		assert.Equal(t, 5, msg.ID)
+		assert.Equal(t, "3", params["message_thread_id"])
+		assert.Equal(t, "true", params["protect_content"])
+
+		msg, err = c.CopyTo(&Chat{ID: 7}, &SendOptions{Caption: "*new*", ParseMode: ModeMarkdown})
+		require.NoError(t, err)
+		assert.Equal(t, 5, msg.ID)
+		assert.Equal(t, "*new*", params["caption"])
+		assert.Equal(t, "42", params["from_chat_id"])
+
+		_, err = b.NewContext(Update{}).CopyTo(&Chat{ID: 7})
+		assert.ErrorIs(t, err, ErrBadContext)
+	})
+	t.Run("SendTemp", func(t *testing.T) {
+		deleted := make(chan string, 3)
+		srv := newTestAPI(t, func(call apiCall) string {
+			if call.Method == "deleteMessage" {
+				deleted <- call.Params["message_id"]
+				return `{"ok":true,"result":true}`
+			}
+			return `{"ok":true,"result":{"message_id":2,"chat":{"id":42}}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Message: &Message{ID: 1, Chat: &Chat{ID: 42}}})
+		require.NoError(t, c.SendTemp("done", 10*time.Millisecond, DeleteCommand))
+		assert.ElementsMatch(t, []string{"1", "2"}, []string{<-deleted, <-deleted})
+
+		require.NoError(t, c.SendTemp("done", time.Hour))
+		b.Stop()
+		assert.Empty(t, b.timers)
+
+		// The context deletion doesn't fire once the bot is stopped
+		c.DeleteAfter(time.Millisecond)
+		select {
+		case id := <-deleted:
+			t.Fatalf("message %s is deleted after Stop", id)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+	t.Run("ReplyWithQuote", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":{"message_id":2}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		c := b.NewContext(Update{Message: &Message{ID: 1, Chat: &Chat{ID: 42}, Text: "say hello"}})
+		require.NoError(t, c.ReplyWithQuote("hi", "hello"))
+		assert.JSONEq(t, `{"message_id":1,"chat_id":42,"quote":"hello","quote_position":4}`, params["reply_parameters"])
+
+		assert.ErrorIs(t, c.ReplyWithQuote("hi", "bye"), ErrQuoteNotFound)
+	})
+	t.Run("AnswerPaged", func(t *testing.T) {
+		var params map[string]string
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			return `{"ok":true,"result":true}`
+		})
+		answered := func() (results []map[string]any) {
+			require.NoError(t, json.Unmarshal([]byte(params["results"]), &results))
+			return results
+		}
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		var results Results
+		for i := 0; i < 5; i++ {
+			results = append(results, &ArticleResult{Title: strconv.Itoa(i), Text: "text"})
+		}
+
+		c := b.NewContext(Update{Query: &Query{ID: "q"}})
+		require.NoError(t, c.AnswerPaged(results, 2, &QueryResponse{CacheTime: 60}))
+		require.Len(t, answered(), 2)
+		assert.Equal(t, "0", answered()[0]["title"])
+		assert.Equal(t, "1", params["next_offset"])
+		assert.Equal(t, "60", params["cache_time"])
+
+		c = b.NewContext(Update{Query: &Query{ID: "q", Offset: "2"}})
+		require.NoError(t, c.AnswerPaged(results, 2))
+		require.Len(t, answered(), 1)
+		assert.Equal(t, "4", answered()[0]["title"])
+		assert.Empty(t, params["next_offset"])
+
+		page, next := (&Query{Offset: "9223372036854775807"}).Page(results, 20)
+		assert.Empty(t, page)
+		assert.Empty(t, next)
+
+		assert.Error(t, b.NewContext(Update{}).AnswerPaged(results, 2))
+	})
+	t.Run("Defer", func(t *testing.T) {
+		var (
+			calls  []string
+			failed = errors.New("failed")
+		)
+		b, err := NewBot(Settings{
+			Offline:     true,
+			Synchronous: true,
+			OnError: func(err error, _ Context) {
+				calls = append(calls, "OnError")
+			},
+		})
+		require.NoError(t, err)
+
+		b.Use(func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				c.Defer(func(err error) {
+					calls = append(calls, "middleware: "+err.Error())
+				})
+				return next(c)
+			}
+		})
+		b.Handle("/fail", func(c Context) error {
+			c.Defer(func(err error) {
+				calls = append(calls, "handler: "+err.Error())
+			})
+			return failed
+		})
+		b.Handle("/abort", func(c Context) error {
+			return c.Abort()
+		})
+
+		b.ProcessUpdate(Update{Message: &Message{Text: "/fail"}})
+		assert.Equal(t, []string{"handler: failed", "middleware: failed", "OnError"}, calls)
+
+		calls = nil
+		b.ProcessUpdate(Update{Message: &Message{Text: "/abort"}})
+		assert.Equal(t, []string{"middleware: " + ErrSkip.Error()}, calls)
+	})
+	t.Run("Business", func(t *testing.T) {
+		var (
+			params  map[string]string
+			fetched int
+		)
+		srv := newTestAPI(t, func(call apiCall) string {
+			params = call.Params
+			if call.Method == "getBusinessConnection" {
+				fetched++
+				return `{"ok":true,"result":{"id":"` + params["business_connection_id"] + `","is_enabled":true,"can_reply":false}}`
+			}
+			return `{"ok":true,"result":{"message_id":2}}`
+		})
+
+		b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+		require.NoError(t, err)
+
+		msg := &Message{ID: 1, Chat: &Chat{ID: 42}, BusinessConnectionID: "conn"}
+		c := b.NewContext(Update{BusinessMessage: msg})
+		assert.Equal(t, "conn", c.BusinessConnectionID())
+
+		assert.Equal(t, ErrBusinessCannotReply, c.Send("text"))
+		assert.Equal(t, ErrBusinessCannotReply, c.Reply("text"))
+		assert.Equal(t, 1, fetched)
+
+		// The connection update refreshes the cache
+		b.ProcessUpdate(Update{BusinessConnection: &BusinessConnection{ID: "conn", Enabled: true, CanReply: true}})
+
+		require.NoError(t, c.Reply("text"))
+		assert.Equal(t, "42", params["chat_id"])
+		assert.Equal(t, "conn", params["business_connection_id"])
+		assert.Equal(t, "1", params["reply_to_message_id"])
+		assert.Equal(t, 1, fetched)
+
+		assert.Empty(t, b.NewContext(Update{Message: &Message{}}).BusinessConnectionID())
+	})
+	t.Run("Mention", func(t *testing.T) {
+		b, err := NewBot(Settings{Offline: true})
+		require.NoError(t, err)
+
+		msg := &Message{
+			Text:     "hey 🙂 @mybot do it",
+			Entities: Entities{{Type: EntityMention, Offset: 7, Length: 6}},
+		}
+		c := b.NewContext(Update{Message: msg})
+
+		// The offline bot has no username
+		assert.False(t, c.IsMentioned())
+		assert.Empty(t, c.MentionText())
+
+		b.setIdentity(&User{ID: 42, Username: "MyBot"})
+		assert.True(t, c.IsMentioned())
+		assert.Equal(t, "do it", c.MentionText())
+
+		c = b.NewContext(Update{Message: &Message{
+			Caption: "Bot, look",
+			CaptionEntities: Entities{
+				{Type: EntityMention, Offset: 0, Length: 3},
+				{Type: EntityTMention, Offset: 0, Length: 3, User: &User{ID: 42}},
+			},
+		}})
+		assert.True(t, c.IsMentioned())
+		assert.Equal(t, ", look", c.MentionText())
+
+		c = b.NewContext(Update{Message: &Message{
+			Text:     "@other hi",
+			Entities: Entities{{Type: EntityMention, Offset: 0, Length: 6}},
+		}})
+		assert.False(t, c.IsMentioned())
+	})
+	t.Run("EffectiveMessage", func(t *testing.T) {
+		b, err := NewBot(Settings{Offline: true})
+		require.NoError(t, err)
+
+		msg := &Message{ID: 1, Sender: &User{ID: 1}}
+		for _, u := range []Update{
+			{Message: msg},
+			{EditedMessage: msg},
+			{ChannelPost: msg},
+			{EditedChannelPost: msg},
+			{BusinessMessage: msg},
+			{EditedBusinessMessage: msg},
+			{Callback: &Callback{Message: msg}},
+		} {
+			assert.Same(t, msg, b.NewContext(u).EffectiveMessage())
+		}
+
+		pinned := &Message{ID: 2, PinnedMessage: msg}
+		assert.Same(t, pinned, b.NewContext(Update{ChannelPost: pinned}).EffectiveMessage())
+
+		inaccessible := &Message{ID: 1, Chat: &Chat{ID: 1}}
+		assert.Nil(t, b.NewContext(Update{Callback: &Callback{Message: inaccessible}}).EffectiveMessage())
+		assert.Nil(t, b.NewContext(Update{Callback: &Callback{}}).EffectiveMessage())
+		assert.Nil(t, b.NewContext(Update{Query: &Query{}}).EffectiveMessage())
+	})
+}
+
+func TestContextCtx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{
+		URL:            srv.URL,
+		Offline:        true,
+		Synchronous:    true,
+		HandlerTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	type traceKey struct{}
+
+	var (
+		ctx     context.Context
+		sendErr error
+	)
+	b.Handle("/start", func(c Context) error {
+		c.SetCtx(context.WithValue(c.Ctx(), traceKey{}, "abc"))
+		ctx = c.Ctx()
+		sendErr = c.Send("hi")
+		return nil
+	})
+
+	start := time.Now()
+	b.ProcessUpdate(Update{Message: &Message{Text: "/start", Chat: &Chat{ID: 1}}})
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorIs(t, sendErr, context.DeadlineExceeded)
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.Equal(t, "abc", ctx.Value(traceKey{}))
+
+	c := b.NewContext(Update{})
+	require.NoError(t, c.Ctx().Err())
+	b.Stop()
+	assert.ErrorIs(t, c.Ctx().Err(), context.Canceled)
 }
@@ -0,0 +1,133 @@
+package telebot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// MaxStartPayload is the maximum length of the deep-linking
+// parameter of the /start command.
+//
+// See https://core.telegram.org/bots/features#deep-linking
+const MaxStartPayload = 64
+
+// startPayloadMACSize is the size of the signature of the
+// signed payloads, see SignStartPayload.
+const startPayloadMACSize = 8
+
+// DeepLink returns the link opening the chat with the bot by its
+// username and sending /start with the payload.
+func DeepLink(username, payload string) string {
+	return "https://t.me/" + username + "?start=" + payload
+}
+
+// ValidateStartPayload checks that the payload may be sent with a deep
+// link: it's up to MaxStartPayload characters long and consists of the
+// characters A-Z, a-z, 0-9, _ and - only.
+func ValidateStartPayload(payload string) error {
+	if len(payload) > MaxStartPayload {
+		return ErrStartPayloadTooLong
+	}
+	for _, r := range payload {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return ErrStartPayloadInvalid
+		}
+	}
+	return nil
+}
+
+// EncodeStartPayload encodes the binary data into the deep-linking
+// payload with URL-safe base64. The payload fits 48 bytes of data.
+func EncodeStartPayload(data []byte) (string, error) {
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	if len(payload) > MaxStartPayload {
+		return "", ErrStartPayloadTooLong
+	}
+	return payload, nil
+}
+
+// DecodeStartPayload decodes the payload made by EncodeStartPayload.
+func DecodeStartPayload(payload string) ([]byte, error) {
+	if err := ValidateStartPayload(payload); err != nil {
+		return nil, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrStartPayloadInvalid
+	}
+	return data, nil
+}
+
+// MarshalStartPayload encodes v as JSON into the deep-linking payload,
+// see EncodeStartPayload. Keep the values short, the JSON must fit
+// 48 bytes.
+func MarshalStartPayload(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return EncodeStartPayload(data)
+}
+
+// UnmarshalStartPayload decodes the payload made by MarshalStartPayload
+// into the value dest points to.
+func UnmarshalStartPayload(payload string, dest any) error {
+	data, err := DecodeStartPayload(payload)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// SignStartPayload encodes the data into the deep-linking payload along
+// with its HMAC-SHA256 signature made with the secret, so the payload
+// can't be forged by users. The payload fits 40 bytes of data.
+func SignStartPayload(data []byte, secret string) (string, error) {
+	return EncodeStartPayload(append(data[:len(data):len(data)], startPayloadMAC(data, secret)...))
+}
+
+// VerifyStartPayload checks the signature of the payload made by
+// SignStartPayload with the same secret and returns its data.
+// Returns ErrStartPayloadSignature if the signature doesn't match.
+func VerifyStartPayload(payload, secret string) ([]byte, error) {
+	data, err := DecodeStartPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < startPayloadMACSize {
+		return nil, ErrStartPayloadSignature
+	}
+
+	data, mac := data[:len(data)-startPayloadMACSize], data[len(data)-startPayloadMACSize:]
+	if !hmac.Equal(startPayloadMAC(data, secret), mac) {
+		return nil, ErrStartPayloadSignature
+	}
+	return data, nil
+}
+
+func startPayloadMAC(data []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return mac.Sum(nil)[:startPayloadMACSize]
+}
+
+// startPayload returns the payload of the /start command
+// in the message text, if any.
+func startPayload(m *Message) string {
+	if m == nil || !strings.HasPrefix(m.Text, "/start") {
+		return ""
+	}
+	command, _, payload, ok := SplitCommand(m.Text)
+	if !ok || command != "/start" {
+		return ""
+	}
+	return strings.TrimSpace(payload)
+}
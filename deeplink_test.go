@@ -0,0 +1,55 @@
+package telebot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartPayload(t *testing.T) {
+	assert.NoError(t, ValidateStartPayload("ref_abc-123"))
+	assert.ErrorIs(t, ValidateStartPayload("ref abc"), ErrStartPayloadInvalid)
+	assert.ErrorIs(t, ValidateStartPayload(strings.Repeat("a", 65)), ErrStartPayloadTooLong)
+
+	payload, err := EncodeStartPayload([]byte("hello?"))
+	require.NoError(t, err)
+	assert.NoError(t, ValidateStartPayload(payload))
+	data, err := DecodeStartPayload(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "hello?", string(data))
+
+	_, err = EncodeStartPayload(make([]byte, 49))
+	assert.ErrorIs(t, err, ErrStartPayloadTooLong)
+
+	type ref struct {
+		From int64  `json:"f"`
+		Tag  string `json:"t"`
+	}
+	payload, err = MarshalStartPayload(ref{From: 42, Tag: "promo"})
+	require.NoError(t, err)
+	var r ref
+	require.NoError(t, UnmarshalStartPayload(payload, &r))
+	assert.Equal(t, ref{From: 42, Tag: "promo"}, r)
+
+	payload, err = SignStartPayload([]byte("user:42"), "secret")
+	require.NoError(t, err)
+	data, err = VerifyStartPayload(payload, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "user:42", string(data))
+
+	_, err = VerifyStartPayload(payload, "other")
+	assert.ErrorIs(t, err, ErrStartPayloadSignature)
+	_, err = VerifyStartPayload("YQ", "secret")
+	assert.ErrorIs(t, err, ErrStartPayloadSignature)
+
+	assert.Equal(t, "https://t.me/bot?start=ref_1", DeepLink("bot", "ref_1"))
+
+	c := NewContext(nil, Update{Message: &Message{Text: "/start ref_abc123"}})
+	assert.Equal(t, "ref_abc123", c.StartPayload())
+	c = NewContext(nil, Update{Message: &Message{Text: "/starter ref"}})
+	assert.Empty(t, c.StartPayload())
+	c = NewContext(nil, Update{Callback: &Callback{Data: "ref"}})
+	assert.Empty(t, c.StartPayload())
+}
@@ -0,0 +1,66 @@
+package telebot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// dumper logs the raw JSON of the incoming updates and of the outgoing
+// requests, see LogConfig.DumpUpdates.
+type dumper struct {
+	logger Logger
+	filter func(chat string) bool
+}
+
+func newDumper(logger Logger, filter func(chat string) bool) *dumper {
+	return &dumper{logger: logger, filter: filter}
+}
+
+func (d *dumper) enabled() bool {
+	return d != nil && d.logger.LogMode() <= LogLevelDebug
+}
+
+// update dumps the update as it came from Telegram, so the fields
+// Update doesn't map are there too.
+func (d *dumper) update(u Update, data []byte) {
+	if !d.enabled() {
+		return
+	}
+
+	var chat string
+	if c := (&nativeContext{u: u}).Chat(); c != nil {
+		chat = strconv.FormatInt(c.ID, 10)
+	}
+	if d.filter != nil && !d.filter(chat) {
+		return
+	}
+	d.logger.Debug("Update %d:\n%s", u.ID, indentJSON(data))
+}
+
+// request dumps the body of the request, the chat is taken from chat_id.
+func (d *dumper) request(method string, data []byte) {
+	if !d.enabled() {
+		return
+	}
+
+	if d.filter != nil {
+		var target struct {
+			ChatID json.RawMessage `json:"chat_id"`
+		}
+		json.Unmarshal(data, &target)
+		if !d.filter(strings.Trim(string(target.ChatID), `"`)) {
+			return
+		}
+	}
+	d.logger.Debug("Request %s:\n%s", method, indentJSON(data))
+}
+
+func indentJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
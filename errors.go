@@ -123,8 +123,30 @@ var (
 	ErrHideRequesterMissing   = NewError(400, "Bad Request: HIDE_REQUESTER_MISSING")
 	ErrChannelsTooMuch        = NewError(400, "Bad Request: CHANNELS_TOO_MUCH")
 	ErrChannelsTooMuchUser    = NewError(400, "Bad Request: USER_CHANNELS_TOO_MUCH")
+	ErrVerifierForbidden      = NewError(400, "Bad Request: BOT_VERIFIER_FORBIDDEN", "bot is not allowed to verify users and chats")
 )
 
+// ErrMessageTooOldToEdit is a special case of ErrCantEditMessage, returned
+// by the edit methods when the message is known to be older than the
+// 48 hours editing window, so errors.Is matches it with both of them.
+// It's never returned by the Err function.
+var ErrMessageTooOldToEdit error = &editWindowError{err: NewError(400, "Bad Request: message can't be edited", "message is too old to be edited")}
+
+// editWindowError is ErrCantEditMessage caused by the editing window.
+type editWindowError struct {
+	err *Error
+}
+
+// Error implements error interface.
+func (err *editWindowError) Error() string {
+	return err.err.Error()
+}
+
+// Is reports whether the target is ErrCantEditMessage.
+func (err *editWindowError) Is(target error) bool {
+	return target == ErrCantEditMessage
+}
+
 // Forbidden errors
 var (
 	ErrBlockedByUser        = NewError(403, "Forbidden: bot was blocked by the user")
@@ -251,6 +273,8 @@ func Err(s string) error {
 		return ErrChannelsTooMuch
 	case ErrChannelsTooMuchUser.ʔ():
 		return ErrChannelsTooMuchUser
+	case ErrVerifierForbidden.ʔ():
+		return ErrVerifierForbidden
 	case ErrNotChannelMember.ʔ():
 		return ErrNotChannelMember
 	default:
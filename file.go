@@ -7,9 +7,16 @@ import (
 
 // File object represents any sort of file.
 type File struct {
-	FileID   string `json:"file_id"`
+	// FileID is used to download or reuse the file. It's specific
+	// to the bot and may change over time for the same file.
+	FileID string `json:"file_id"`
+
+	// UniqueID is supposed to be the same over time and for different
+	// bots, but it can't be used to download or reuse the file.
+	// Store it when you need to tell whether two files are the same.
 	UniqueID string `json:"file_unique_id"`
-	FileSize int64  `json:"file_size"`
+
+	FileSize int64 `json:"file_size"`
 
 	// FilePath is used for files on Telegram server.
 	FilePath string `json:"file_path"`
@@ -80,6 +87,13 @@ func (f *File) InCloud() bool {
 	return f.FileID != ""
 }
 
+// SameAs tells whether both files refer to the same underlying
+// file on Telegram servers, comparing their unique identifiers.
+// A nil file is never the same as any other.
+func (f *File) SameAs(g *File) bool {
+	return f != nil && g != nil && f.UniqueID != "" && f.UniqueID == g.UniqueID
+}
+
 // OnDisk will return true if file is present on disk.
 func (f *File) OnDisk() bool {
 	_, err := os.Stat(f.FileLocal)
@@ -1,10 +1,15 @@
 package telebot
 
 import (
+	"bytes"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFile(t *testing.T) {
@@ -22,3 +27,56 @@ func TestFile(t *testing.T) {
 	assert.Equal(t, g.FileLocal, f.FileLocal)
 	assert.Equal(t, f.FileURL, g.FileURL)
 }
+
+func TestFileSameAs(t *testing.T) {
+	f := File{FileID: "1", UniqueID: "unique"}
+	g := File{FileID: "2", UniqueID: "unique"}
+
+	assert.True(t, f.SameAs(&g))
+	assert.False(t, f.SameAs(&File{UniqueID: "other"}))
+	assert.False(t, (&File{}).SameAs(&File{}))
+	assert.False(t, f.SameAs(nil))
+	assert.False(t, (*File)(nil).SameAs(&g))
+
+	m := &Message{Document: &Document{File: f}}
+	assert.Equal(t, "unique", m.MediaFileUniqueID())
+	assert.Empty(t, (&Message{Text: "text"}).MediaFileUniqueID())
+}
+
+func TestDownloadTo(t *testing.T) {
+	content := strings.Repeat("x", 100000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getFile") {
+			w.Write([]byte(`{"ok":true,"result":{"file_id":"doc","file_path":"documents/doc.txt","file_size":100000}}`))
+			return
+		}
+		assert.Equal(t, "/file/bottoken/documents/doc.txt", r.URL.Path)
+		io.WriteString(w, content)
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{URL: srv.URL, Token: "token", Offline: true})
+	require.NoError(t, err)
+
+	var (
+		buf     bytes.Buffer
+		written int64
+		total   int64
+	)
+	file := &File{FileID: "doc"}
+	err = b.DownloadTo(file, &buf, func(w, t int64) { written, total = w, t })
+	require.NoError(t, err)
+	assert.Equal(t, content, buf.String())
+	assert.EqualValues(t, 100000, written)
+	assert.EqualValues(t, 100000, total)
+	assert.Equal(t, "documents/doc.txt", file.FilePath)
+
+	doc := &Message{Document: &Document{File: File{FileID: "doc"}}}
+	c := NewContext(b, Update{Message: &Message{Text: "save", ReplyTo: doc}})
+	buf.Reset()
+	require.NoError(t, c.DownloadReplyDocument(&buf))
+	assert.Equal(t, content, buf.String())
+
+	c = NewContext(b, Update{Message: &Message{Text: "save"}})
+	assert.ErrorIs(t, c.DownloadReplyDocument(&buf), ErrNoDocument)
+}
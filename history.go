@@ -0,0 +1,138 @@
+package telebot
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// updateHistory is a ring buffer of the last processed updates.
+type updateHistory struct {
+	mu     sync.Mutex
+	redact func(Update) Update
+	buf    []Update
+	next   int
+	full   bool
+}
+
+func newUpdateHistory(size int, redact func(Update) Update) *updateHistory {
+	return &updateHistory{
+		redact: redact,
+		buf:    make([]Update, size),
+	}
+}
+
+func (h *updateHistory) add(u Update) {
+	// The handlers change the message and the callback in place,
+	// e.g. the callback data is stripped of the unique, so the
+	// history keeps a deep copy of the update as it was received.
+	u = copyUpdate(u)
+	if h.redact != nil {
+		u = h.redact(u)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf[h.next] = u
+	h.next = (h.next + 1) % len(h.buf)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// copyUpdate returns a copy of the update sharing no values with it.
+func copyUpdate(u Update) Update {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return u
+	}
+	var cp Update
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return u
+	}
+	return cp
+}
+
+// list returns the updates from the oldest to the newest.
+func (h *updateHistory) list() []Update {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]Update(nil), h.buf[:h.next]...)
+	}
+
+	updates := make([]Update, 0, len(h.buf))
+	updates = append(updates, h.buf[h.next:]...)
+	return append(updates, h.buf[:h.next]...)
+}
+
+// RecentUpdates returns the last updates processed by the bot, from
+// the oldest to the newest, see Settings.UpdateHistory. It's meant for
+// debugging, e.g. to dump the recent traffic from an admin command,
+// and the history is not kept across restarts.
+//
+// Returns nil if the history is disabled.
+func (b *Bot) RecentUpdates() []Update {
+	if b.history == nil {
+		return nil
+	}
+	return b.history.list()
+}
+
+// unhandledTypes counts the updates which matched no handler,
+// by their type. There are only so many types, so it's bounded.
+type unhandledTypes struct {
+	mu     sync.Mutex
+	logger Logger
+	counts map[UpdateType]int
+}
+
+func newUnhandledTypes(logger Logger) *unhandledTypes {
+	return &unhandledTypes{
+		logger: logger,
+		counts: make(map[UpdateType]int),
+	}
+}
+
+func (u *unhandledTypes) add(typ UpdateType) {
+	if typ == "" {
+		return
+	}
+
+	u.mu.Lock()
+	u.counts[typ]++
+	first := u.counts[typ] == 1
+	u.mu.Unlock()
+
+	if first {
+		u.logger.Info("Received %s update, which no handler matched", typ)
+	}
+}
+
+// markHandled notes that a handler is picked for the context.
+func markHandled(c Context) {
+	if nc, ok := c.(*nativeContext); ok {
+		nc.handled = true
+	}
+}
+
+// UnhandledTypes returns the number of updates of each type which
+// matched no handler, see Settings.TrackUnhandled. It helps to find
+// out the updates the bot receives, but silently ignores.
+//
+// Returns nil if the tracking is disabled.
+func (b *Bot) UnhandledTypes() map[UpdateType]int {
+	if b.unhandled == nil {
+		return nil
+	}
+
+	b.unhandled.mu.Lock()
+	defer b.unhandled.mu.Unlock()
+
+	counts := make(map[UpdateType]int, len(b.unhandled.counts))
+	for typ, n := range b.unhandled.counts {
+		counts[typ] = n
+	}
+	return counts
+}
@@ -0,0 +1,132 @@
+package telebot
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentUpdates(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	b.ProcessUpdate(Update{ID: 1})
+	assert.Nil(t, b.RecentUpdates())
+
+	b, err = NewBot(Settings{
+		Offline:       true,
+		Synchronous:   true,
+		UpdateHistory: 3,
+		RedactUpdate: func(u Update) Update {
+			if u.Message != nil {
+				m := *u.Message
+				m.Text = "<redacted>"
+				u.Message = &m
+			}
+			return u
+		},
+	})
+	require.NoError(t, err)
+
+	var text string
+	b.Handle(OnText, func(c Context) error {
+		text = c.Text()
+		return nil
+	})
+
+	b.ProcessUpdate(Update{ID: 1, Message: &Message{Text: "secret"}})
+	assert.Equal(t, "secret", text)
+
+	updates := b.RecentUpdates()
+	require.Len(t, updates, 1)
+	assert.Equal(t, "<redacted>", updates[0].Message.Text)
+
+	for i := 2; i <= 5; i++ {
+		b.ProcessUpdate(Update{ID: i})
+	}
+
+	var ids []int
+	for _, u := range b.RecentUpdates() {
+		ids = append(ids, u.ID)
+	}
+	assert.Equal(t, []int{3, 4, 5}, ids)
+
+	// The history keeps the update as it was received
+	b.Handle(&Btn{Unique: "next"}, func(c Context) error { return nil })
+	b.ProcessUpdate(Update{ID: 6, Callback: &Callback{Data: "\fnext|2"}})
+	updates = b.RecentUpdates()
+	assert.Equal(t, "\fnext|2", updates[len(updates)-1].Callback.Data)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.ProcessUpdate(Update{ID: 7})
+			b.RecentUpdates()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, b.RecentUpdates(), 3)
+}
+
+func TestUnhandledTypes(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true})
+	require.NoError(t, err)
+	b.ProcessUpdate(Update{PollAnswer: &PollAnswer{}})
+	assert.Nil(t, b.UnhandledTypes())
+
+	logger := NewCustomTestLogger()
+	b, err = NewBot(Settings{
+		Offline:        true,
+		Synchronous:    true,
+		TrackUnhandled: true,
+		Log:            &LogConfig{Enable: true, Level: LogLevelInfo, Logger: logger},
+	})
+	require.NoError(t, err)
+	b.Handle(OnText, func(c Context) error { return nil })
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "hi", Chat: &Chat{ID: 1}}})
+	b.ProcessUpdate(Update{Message: &Message{Sticker: &Sticker{}, Chat: &Chat{ID: 1}}})
+	b.ProcessUpdate(Update{PollAnswer: &PollAnswer{}})
+	b.ProcessUpdate(Update{PollAnswer: &PollAnswer{}})
+	b.ProcessUpdate(Update{ID: 1})
+
+	assert.Equal(t, map[UpdateType]int{"message": 1, "poll_answer": 2}, b.UnhandledTypes())
+	assert.Equal(t, 1, strings.Count(logger.GetOutput(), "Received poll_answer update, which no handler matched"))
+}
+
+func TestOnAny(t *testing.T) {
+	var unmatched []UpdateType
+	b, err := NewBot(Settings{
+		Offline:     true,
+		Synchronous: true,
+		OnUnmatched: func(c Context) { unmatched = append(unmatched, c.Update().Type()) },
+	})
+	require.NoError(t, err)
+	b.Handle(OnText, func(c Context) error { return nil })
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "hi", Chat: &Chat{ID: 1}}})
+	b.ProcessUpdate(Update{PollAnswer: &PollAnswer{}})
+	assert.Equal(t, []UpdateType{"poll_answer"}, unmatched)
+
+	var caught []UpdateType
+	b.Handle(OnAny, func(c Context) error {
+		caught = append(caught, c.Update().Type())
+		return nil
+	})
+	b.ProcessUpdate(Update{Message: &Message{Text: "hi", Chat: &Chat{ID: 1}}})
+	b.ProcessUpdate(Update{PollAnswer: &PollAnswer{}})
+	b.ProcessUpdate(Update{Callback: &Callback{Data: "x"}})
+	assert.Equal(t, []UpdateType{"poll_answer", "callback_query"}, caught)
+	assert.Len(t, unmatched, 1)
+}
+
+func TestUpdateType(t *testing.T) {
+	assert.Equal(t, UpdateType("edited_channel_post"), Update{EditedChannelPost: &Message{}}.Type())
+	assert.Equal(t, UpdateType("callback_query"), Update{Callback: &Callback{}}.Type())
+	assert.Equal(t, UpdateType(""), Update{ID: 1}.Type())
+}
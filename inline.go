@@ -3,8 +3,13 @@ package telebot
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
+// MaxQueryResults is the maximum number of results
+// in a response to an inline query.
+const MaxQueryResults = 50
+
 // Query is an incoming inline query. When the user sends
 // an empty query, your bot could return some default or
 // trending results.
@@ -67,6 +72,34 @@ type QueryResponse struct {
 	Button *QueryResponseButton `json:"button,omitempty"`
 }
 
+// Page returns the page of the results the query asks for, pageSize
+// results per page, along with the offset of the next page, which is
+// empty for the last one. The offset is the page number, see
+// Context.AnswerPaged.
+func (q *Query) Page(results Results, pageSize int) (page Results, nextOffset string) {
+	if pageSize <= 0 || pageSize > MaxQueryResults {
+		pageSize = MaxQueryResults
+	}
+
+	n, err := strconv.Atoi(q.Offset)
+	if err != nil || n < 0 {
+		n = 0
+	}
+
+	// The offset comes from the user, so it may be past the last page
+	// far enough to overflow the multiplication.
+	if n >= (len(results)+pageSize-1)/pageSize {
+		return Results{}, ""
+	}
+
+	start := n * pageSize
+	end := min(start+pageSize, len(results))
+	if end < len(results) {
+		nextOffset = strconv.Itoa(n + 1)
+	}
+	return results[start:end], nextOffset
+}
+
 // QueryResponseButton represents a button to be shown above inline query results.
 // You must use exactly one of the optional fields.
 type QueryResponseButton struct {
@@ -56,10 +56,16 @@ func (r *ResultBase) Process(b *Bot) {
 		}
 	}
 	if r.ReplyMarkup != nil {
-		processButtons(r.ReplyMarkup.InlineKeyboard)
+		// The states are stored beforehand by storeResultStates,
+		// since Process can't report the error.
+		b.processButtons(r.ReplyMarkup.InlineKeyboard)
 	}
 }
 
+func (r *ResultBase) replyMarkup() *ReplyMarkup {
+	return r.ReplyMarkup
+}
+
 // GameResult represents a game. Game is a content type
 // supported by Telegram, which can be sent back to the
 // user as a result for an inline query.
@@ -0,0 +1,34 @@
+package telebot
+
+import (
+	"errors"
+	"strconv"
+)
+
+// localeKey returns the SessionStore key of the user's locale.
+func localeKey(user *User) string {
+	return "locale:" + strconv.FormatInt(user.ID, 10)
+}
+
+// UserLocale returns the locale the user chose, set with SetUserLocale.
+// It's kept in the SessionStore, apart from the sessions of the chats.
+// Returns an empty string if the user hasn't chosen any.
+func (b *Bot) UserLocale(user *User) (string, error) {
+	data, err := b.sessionStore().Get(localeKey(user))
+	if errors.Is(err, ErrSessionNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SetUserLocale persists the locale the user chose, see Context.Locale.
+// An empty locale removes the choice.
+func (b *Bot) SetUserLocale(user *User, locale string) error {
+	if locale == "" {
+		return b.sessionStore().Delete(localeKey(user))
+	}
+	return b.sessionStore().Set(localeKey(user), []byte(locale), 0)
+}
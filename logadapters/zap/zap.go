@@ -0,0 +1,99 @@
+// Package zap adapts go.uber.org/zap loggers to the telebot Logger interface.
+// It's a module of its own, so only the bots using it depend on zap.
+package zap
+
+import (
+	tele "github.com/nullcache/telebotx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger wraps zap's Logger to implement telebot's Logger
+// and FieldLogger interfaces.
+type Logger struct {
+	logger     *zap.SugaredLogger
+	fatalLevel zapcore.Level
+}
+
+// Option configures the Logger.
+type Option func(*Logger)
+
+// FatalLevel sets the level of the fatal messages, zapcore.FatalLevel by
+// default, which makes zap exit after writing them. Pass a lower level,
+// e.g. zapcore.ErrorLevel, to keep the process running.
+func FatalLevel(level zapcore.Level) Option {
+	return func(l *Logger) {
+		l.fatalLevel = level
+	}
+}
+
+// New creates a new Logger that wraps the provided zap.Logger.
+func New(logger *zap.Logger, opts ...Option) *Logger {
+	if logger == nil {
+		logger = zap.L()
+	}
+	l := &Logger{
+		// Skip the adapter frames, so the caller is reported.
+		logger:     logger.WithOptions(zap.AddCallerSkip(2)).Sugar(),
+		fatalLevel: zapcore.FatalLevel,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// log passes the message to zap, see tele.SplitLogArgs.
+func (l *Logger) log(level zapcore.Level, msg string, args ...any) {
+	msg, args = tele.SplitLogArgs(msg, args)
+	l.logger.Logw(level, msg, args...)
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(zapcore.DebugLevel, msg, args...)
+}
+
+// Info logs an info message
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(zapcore.InfoLevel, msg, args...)
+}
+
+// Warn logs a warning message
+func (l *Logger) Warn(msg string, args ...any) {
+	l.log(zapcore.WarnLevel, msg, args...)
+}
+
+// Error logs an error message
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(zapcore.ErrorLevel, msg, args...)
+}
+
+// Fatal logs a fatal message at the level set by FatalLevel
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(l.fatalLevel, msg, args...)
+}
+
+// With returns a logger which passes the key-value pairs to zap
+// along with every message
+func (l *Logger) With(args ...any) tele.Logger {
+	return &Logger{logger: l.logger.With(args...), fatalLevel: l.fatalLevel}
+}
+
+// LogMode returns the lowest level enabled by the zap core
+func (l *Logger) LogMode() tele.LogLevel {
+	levels := []zapcore.Level{
+		zapcore.DebugLevel,
+		zapcore.InfoLevel,
+		zapcore.WarnLevel,
+		zapcore.ErrorLevel,
+		zapcore.FatalLevel,
+	}
+	core := l.logger.Desugar().Core()
+	for i, level := range levels {
+		if core.Enabled(level) {
+			return tele.LogLevelDebug + tele.LogLevel(i)
+		}
+	}
+	return tele.LogLevelOff
+}
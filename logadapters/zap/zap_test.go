@@ -0,0 +1,42 @@
+package zap
+
+import (
+	"testing"
+
+	tele "github.com/nullcache/telebotx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var _ tele.FieldLogger = (*Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := New(zap.New(core), FatalLevel(zapcore.ErrorLevel))
+	assert.Equal(t, tele.LogLevelInfo, logger.LogMode())
+
+	logger.Debug("hidden")
+	logger.Info("update %d received", 42)
+	logger.Warn("slow handler", "chat", int64(7))
+	logger.With("chat_id", int64(42)).Error("failed")
+	logger.Fatal("fatal, but still running")
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 4)
+
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "update 42 received", entries[0].Message)
+	assert.Empty(t, entries[0].Context)
+
+	assert.Equal(t, "slow handler", entries[1].Message)
+	assert.Equal(t, []zapcore.Field{zap.Int64("chat", 7)}, entries[1].Context)
+
+	assert.Equal(t, zapcore.ErrorLevel, entries[2].Level)
+	assert.Equal(t, []zapcore.Field{zap.Int64("chat_id", 42)}, entries[2].Context)
+
+	assert.Equal(t, zapcore.ErrorLevel, entries[3].Level)
+
+	assert.Equal(t, tele.LogLevelOff, New(zap.NewNop()).LogMode())
+}
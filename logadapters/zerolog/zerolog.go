@@ -0,0 +1,130 @@
+// Package zerolog adapts github.com/rs/zerolog loggers to the telebot
+// Logger interface. It's a module of its own, so only the bots using it
+// depend on zerolog.
+package zerolog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	tele "github.com/nullcache/telebotx"
+	"github.com/rs/zerolog"
+)
+
+// Logger wraps zerolog's Logger to implement telebot's Logger
+// and FieldLogger interfaces.
+type Logger struct {
+	logger     zerolog.Logger
+	fatalLevel zerolog.Level
+}
+
+// Option configures the Logger.
+type Option func(*Logger)
+
+// FatalLevel sets the level of the fatal messages, zerolog.FatalLevel by
+// default, which makes the logger exit after writing them. Pass a lower
+// level, e.g. zerolog.ErrorLevel, to keep the process running.
+func FatalLevel(level zerolog.Level) Option {
+	return func(l *Logger) {
+		l.fatalLevel = level
+	}
+}
+
+// BotFields adds the bot username and a short hash of its token to every
+// message, so the logs of several bots writing to one sink can be told
+// apart. The token itself is never logged.
+func BotFields(b *tele.Bot) Option {
+	return func(l *Logger) {
+		ctx := l.logger.With().Str("token_hash", TokenHash(b.Token))
+		if me := b.Identity(); me != nil && me.Username != "" {
+			ctx = ctx.Str("bot", me.Username)
+		}
+		l.logger = ctx.Logger()
+	}
+}
+
+// TokenHash returns the first 8 hex digits of the SHA-256 of the token,
+// as logged by BotFields.
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
+
+// New creates a new Logger that wraps the provided zerolog.Logger.
+func New(logger zerolog.Logger, opts ...Option) *Logger {
+	l := &Logger{
+		logger:     logger,
+		fatalLevel: zerolog.FatalLevel,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// log passes the message to zerolog, see tele.SplitLogArgs.
+func (l *Logger) log(level zerolog.Level, msg string, args ...any) {
+	var event *zerolog.Event
+	if level == zerolog.FatalLevel {
+		// Unlike WithLevel, Fatal exits once the message is written.
+		event = l.logger.Fatal()
+	} else {
+		event = l.logger.WithLevel(level)
+	}
+
+	msg, args = tele.SplitLogArgs(msg, args)
+	event.Fields(args).Msg(msg)
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(zerolog.DebugLevel, msg, args...)
+}
+
+// Info logs an info message
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(zerolog.InfoLevel, msg, args...)
+}
+
+// Warn logs a warning message
+func (l *Logger) Warn(msg string, args ...any) {
+	l.log(zerolog.WarnLevel, msg, args...)
+}
+
+// Error logs an error message
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(zerolog.ErrorLevel, msg, args...)
+}
+
+// Fatal logs a fatal message at the level set by FatalLevel
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(l.fatalLevel, msg, args...)
+}
+
+// With returns a logger which passes the key-value pairs to zerolog
+// along with every message
+func (l *Logger) With(args ...any) tele.Logger {
+	return &Logger{
+		logger:     l.logger.With().Fields(args).Logger(),
+		fatalLevel: l.fatalLevel,
+	}
+}
+
+// LogMode returns the lowest level enabled by both the logger
+// and the zerolog global level
+func (l *Logger) LogMode() tele.LogLevel {
+	switch max(l.logger.GetLevel(), zerolog.GlobalLevel()) {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return tele.LogLevelDebug
+	case zerolog.InfoLevel:
+		return tele.LogLevelInfo
+	case zerolog.WarnLevel:
+		return tele.LogLevelWarn
+	case zerolog.ErrorLevel:
+		return tele.LogLevelError
+	case zerolog.FatalLevel:
+		return tele.LogLevelFatal
+	default:
+		return tele.LogLevelOff
+	}
+}
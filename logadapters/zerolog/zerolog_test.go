@@ -0,0 +1,50 @@
+package zerolog
+
+import (
+	"bytes"
+	"testing"
+
+	tele "github.com/nullcache/telebotx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ tele.FieldLogger = (*Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(zerolog.New(&buf).Level(zerolog.InfoLevel), FatalLevel(zerolog.ErrorLevel))
+	assert.Equal(t, tele.LogLevelInfo, logger.LogMode())
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	logger.Info("update %d received", 42)
+	assert.JSONEq(t, `{"level":"info","message":"update 42 received"}`, buf.String())
+	buf.Reset()
+
+	logger.Warn("slow handler", "chat", 7)
+	assert.JSONEq(t, `{"level":"warn","chat":7,"message":"slow handler"}`, buf.String())
+	buf.Reset()
+
+	logger.With("chat_id", 42).Error("failed")
+	assert.JSONEq(t, `{"level":"error","chat_id":42,"message":"failed"}`, buf.String())
+	buf.Reset()
+
+	logger.Fatal("fatal, but still running")
+	assert.JSONEq(t, `{"level":"error","message":"fatal, but still running"}`, buf.String())
+
+	assert.Equal(t, tele.LogLevelOff, New(zerolog.Nop()).LogMode())
+}
+
+func TestBotFields(t *testing.T) {
+	b, err := tele.NewBot(tele.Settings{Token: "123:secret", Offline: true})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	New(zerolog.New(&buf), BotFields(b)).Info("started")
+	assert.JSONEq(t, `{"level":"info","token_hash":"`+TokenHash("123:secret")+`","message":"started"}`, buf.String())
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Len(t, TokenHash("123:secret"), 8)
+}
@@ -0,0 +1,173 @@
+package telebot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedTimeFormat is the suffix of the rotated log files.
+const rotatedTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is an io.Writer appending to the file at Path, which is
+// rotated once it grows over MaxSize or gets older than MaxAge. The
+// rotated files are renamed to Path with the time of rotation appended.
+// It's meant to be one of LogConfig.Sinks:
+//
+//	Log: &tele.LogConfig{
+//		Enable: true,
+//		Sinks: []io.Writer{
+//			os.Stdout,
+//			&tele.RotatingFile{Path: "bot.log", MaxSize: 10 << 20, MaxBackups: 5},
+//		},
+//	}
+type RotatingFile struct {
+	// Path is the path of the current log file.
+	Path string
+
+	// MaxSize is the size in bytes the file is rotated at, not limited if zero.
+	MaxSize int64
+
+	// MaxAge is the age the file is rotated at, not limited if zero.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of the rotated files to keep,
+	// all of them are kept if zero.
+	MaxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// Write appends p to the file, rotating it first if needed.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.due(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file regardless of its size and age.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Close closes the current file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) due(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.MaxSize > 0 && f.size+int64(n) > f.MaxSize {
+		return true
+	}
+	return f.MaxAge > 0 && time.Since(f.opened) >= f.MaxAge
+}
+
+// open opens the file for appending, the existing file is kept.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("telebot: can't open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("telebot: can't open log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = time.Now()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("telebot: can't rotate log file: %w", err)
+		}
+		f.file = nil
+	}
+
+	backup := f.Path + "." + time.Now().Format(rotatedTimeFormat)
+	if err := os.Rename(f.Path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("telebot: can't rotate log file: %w", err)
+	}
+	f.removeBackups()
+
+	return f.open()
+}
+
+// removeBackups removes the oldest rotated files over MaxBackups.
+// The time suffixes sort in the order of rotation.
+func (f *RotatingFile) removeBackups() {
+	if f.MaxBackups <= 0 {
+		return
+	}
+
+	backups := f.backups()
+	if len(backups) <= f.MaxBackups {
+		return
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// backups returns the paths of the rotated files. Only the names made of
+// Path and a rotation time are matched, so other files next to it, such
+// as "bot.go" for "bot", are never removed.
+func (f *RotatingFile) backups() []string {
+	dir, base := filepath.Split(f.Path)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), base+".")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(rotatedTimeFormat, suffix); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, entry.Name()))
+	}
+	return backups
+}
@@ -1,8 +1,17 @@
 package telebot
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // LogLevel represents the logging level
@@ -37,6 +46,34 @@ func (l LogLevel) String() string {
 	}
 }
 
+// LogFormat represents the output format of DefaultLogger
+type LogFormat string
+
+const (
+	// LogFormatText writes the messages as plain text lines
+	LogFormatText LogFormat = "text"
+
+	// LogFormatJSON writes one JSON object per line with the time,
+	// the level, the prefix, the formatted message and its args
+	LogFormatJSON LogFormat = "json"
+)
+
+// FatalBehavior is what DefaultLogger and SlogLogger do after writing
+// a fatal message
+type FatalBehavior int
+
+const (
+	// FatalExit exits the process with os.Exit(1)
+	FatalExit FatalBehavior = iota
+
+	// FatalPanic panics with the formatted message, which can
+	// be recovered from
+	FatalPanic
+
+	// FatalLogOnly only writes the message, as Error does
+	FatalLogOnly
+)
+
 // LogConfig represents the logging configuration
 type LogConfig struct {
 	// Enable controls whether logging is enabled
@@ -49,8 +86,50 @@ type LogConfig struct {
 	// Prefix is the prefix for log messages
 	Prefix string
 
+	// Format is the output format of the default logger,
+	// LogFormatText if empty
+	Format LogFormat
+
+	// Sinks are the writers the default logger writes every message to,
+	// os.Stdout if empty. See RotatingFile for logging to a file.
+	Sinks []io.Writer
+
 	// Logger is the logger implementation to use.
 	Logger Logger
+
+	// FatalBehavior is what the default logger does on Fatal, FatalExit
+	// if not set. The framework itself never logs at the fatal level.
+	FatalBehavior FatalBehavior
+
+	// DisableRedaction turns off removing the secrets from the framework
+	// log output. By default, the bot token, the webhook secret token, the
+	// payment provider tokens and Secrets are replaced with "<redacted>",
+	// even in the HTTP errors embedding the request URL.
+	DisableRedaction bool
+
+	// Secrets are the extra strings to redact from the log output.
+	Secrets []string
+
+	// Levels overrides the minimum level of the named loggers, such as
+	// "poller", "webhook" or "handler:/start", see Bot.Logger. With the
+	// default logger, the override can be lower than Level, so a single
+	// handler can be debugged without the debug output of the rest.
+	Levels map[string]LogLevel
+
+	// DumpUpdates logs the raw JSON of every incoming update and every
+	// outgoing request body at the debug level, through the "dump" named
+	// logger. It shows the fields Telegram sends, which aren't mapped yet.
+	DumpUpdates bool
+
+	// DumpFilter limits the dumping to the chats it returns true for.
+	// The chat is the chat ID or the @username of the request, empty
+	// if there is no chat.
+	DumpFilter func(chat string) bool
+
+	// TraceAPI logs every Bot API call at the debug level: the method,
+	// the parameters, the duration, the HTTP status and the outcome.
+	// The token is redacted and the uploaded files are only named.
+	TraceAPI bool
 }
 
 // Logger represents a generic logging interface that can be implemented
@@ -64,60 +143,288 @@ type Logger interface {
 	LogMode() LogLevel
 }
 
+// SplitLogArgs tells apart the two ways the Logger methods are called.
+// The framework writes printf-style messages, Info("update %d received", id),
+// while the structured calls pass key-value pairs, Info("slow handler",
+// "chat", id). The message is formatted only if its verbs take all the
+// args, so a literal '%', as in "disk 90% full", keeps the pairs intact.
+// It returns the message to write and the pairs, nil once formatted.
+// The loggers of the structured logging libraries, such as SlogLogger,
+// use it to pass the pairs on as fields.
+func SplitLogArgs(msg string, args []any) (string, []any) {
+	if len(args) == 0 {
+		return msg, args
+	}
+	if n, indexed := countVerbs(msg); indexed || n == len(args) {
+		return fmt.Sprintf(msg, args...), nil
+	}
+	return msg, args
+}
+
+// countVerbs returns the number of args the format takes,
+// and whether it uses the explicit arg indexes.
+func countVerbs(format string) (n int, indexed bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i++; i < len(format) && format[i] == '%' {
+			continue
+		}
+		for ; i < len(format); i++ {
+			c := format[i]
+			if c == '[' {
+				indexed = true
+			} else if c == '*' {
+				n++
+			} else if strings.IndexByte("+-# .]0123456789", c) < 0 {
+				n++
+				break
+			}
+		}
+	}
+	return n, indexed
+}
+
+// FieldLogger is a Logger which can attach key-value pairs to every
+// message it writes, e.g. the chat and the user of the update.
+//
+// Implementing it is optional, see LoggerWith.
+type FieldLogger interface {
+	Logger
+	With(args ...any) Logger
+}
+
+// LoggerWith returns a logger which writes the key-value pairs along
+// with every message. For the loggers that don't implement FieldLogger
+// the pairs are appended to the message text.
+func LoggerWith(l Logger, args ...any) Logger {
+	if len(args) == 0 {
+		return l
+	}
+	if fl, ok := l.(FieldLogger); ok {
+		return fl.With(args...)
+	}
+	return &fieldsLogger{Logger: l, fields: formatFields(args)}
+}
+
+// ContextLogKey is a context.Context key, whose value LoggerWithContext
+// writes as the field named after the key.
+type ContextLogKey string
+
+const (
+	// TraceIDKey is the key of the distributed trace ID.
+	TraceIDKey ContextLogKey = "trace_id"
+
+	// TenantIDKey is the key of the tenant ID.
+	TenantIDKey ContextLogKey = "tenant_id"
+)
+
+// ContextLogKeys are the context.Context keys LoggerWithContext looks up.
+// Keys of other types are named with fmt.Sprint, so the keys of tracing
+// libraries can be added here as long as they print nicely.
+var ContextLogKeys = []any{TraceIDKey, TenantIDKey}
+
+// LoggerWithContext returns a logger which writes the values of
+// ContextLogKeys found in ctx along with every message, so the bot
+// logs line up with the traces of the request.
+func LoggerWithContext(l Logger, ctx context.Context) Logger {
+	if ctx == nil {
+		return l
+	}
+
+	var args []any
+	for _, key := range ContextLogKeys {
+		v := ctx.Value(key)
+		if v == nil {
+			continue
+		}
+		if name, ok := key.(ContextLogKey); ok {
+			args = append(args, string(name), v)
+		} else {
+			args = append(args, fmt.Sprint(key), v)
+		}
+	}
+	return LoggerWith(l, args...)
+}
+
+// fieldsLogger appends the formatted fields to the messages
+// of a printf-style logger.
+type fieldsLogger struct {
+	Logger
+	fields string
+}
+
+func (l *fieldsLogger) Debug(msg string, args ...any) { l.Logger.Debug(msg+l.fields, args...) }
+func (l *fieldsLogger) Info(msg string, args ...any)  { l.Logger.Info(msg+l.fields, args...) }
+func (l *fieldsLogger) Warn(msg string, args ...any)  { l.Logger.Warn(msg+l.fields, args...) }
+func (l *fieldsLogger) Error(msg string, args ...any) { l.Logger.Error(msg+l.fields, args...) }
+func (l *fieldsLogger) Fatal(msg string, args ...any) { l.Logger.Fatal(msg+l.fields, args...) }
+
+func (l *fieldsLogger) With(args ...any) Logger {
+	return &fieldsLogger{Logger: l.Logger, fields: l.fields + formatFields(args)}
+}
+
+// formatFields formats the key-value pairs as " key=value", escaped
+// for printf. A key without a value is reported the way slog does.
+func formatFields(args []any) string {
+	var sb strings.Builder
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&sb, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&sb, " %v=%v", args[i], args[i+1])
+	}
+	return strings.ReplaceAll(sb.String(), "%", "%%")
+}
+
 type DefaultLogger struct {
 	logger  *log.Logger
 	enabled bool
-	level   LogLevel
+	level   atomic.Int32
+
+	// json is set for LogFormatJSON, the prefix is written as a field.
+	json   bool
+	prefix string
+
+	fatal atomic.Int32
 }
 
 // NewDefaultLogger creates a new DefaultLogger instance with custom configuration.
 func NewDefaultLogger(level LogLevel, prefix string) *DefaultLogger {
-	return &DefaultLogger{
+	l := &DefaultLogger{
 		logger:  log.New(os.Stdout, prefix, log.LstdFlags|log.Lshortfile),
 		enabled: true,
-		level:   level,
 	}
+	l.level.Store(int32(level))
+	return l
+}
+
+// NewDefaultJSONLogger creates a new DefaultLogger which writes
+// the messages in LogFormatJSON.
+func NewDefaultJSONLogger(level LogLevel, prefix string) *DefaultLogger {
+	l := &DefaultLogger{
+		logger:  log.New(os.Stdout, "", 0),
+		enabled: true,
+		json:    true,
+		prefix:  prefix,
+	}
+	l.level.Store(int32(level))
+	return l
+}
+
+// jsonEntry is a line written in LogFormatJSON.
+type jsonEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Prefix  string `json:"prefix,omitempty"`
+	Message string `json:"msg"`
+	Args    []any  `json:"args,omitempty"`
+}
+
+func (l *DefaultLogger) print(level LogLevel, msg string, args []any) {
+	if !l.json {
+		l.logger.Printf("["+level.String()+"] "+msg, args...)
+		return
+	}
+
+	entry := jsonEntry{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Level:   level.String(),
+		Prefix:  l.prefix,
+		Message: fmt.Sprintf(msg, args...),
+	}
+	for _, arg := range args {
+		// Errors and most of the other interfaces marshal to {}.
+		if err, ok := arg.(error); ok {
+			arg = err.Error()
+		}
+		entry.Args = append(entry.Args, arg)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// Some of the args can't be marshaled, keep their text.
+		for i, arg := range entry.Args {
+			entry.Args[i] = fmt.Sprint(arg)
+		}
+		line, _ = json.Marshal(entry)
+	}
+	l.logger.Print(string(line))
+}
+
+// printAt writes the message regardless of the minimum level,
+// see LogConfig.Levels.
+func (l *DefaultLogger) printAt(level LogLevel, msg string, args []any) {
+	if !l.enabled {
+		return
+	}
+	l.print(level, msg, args)
+}
+
+// SetLevel changes the minimum log level, it's safe to call
+// while the bot is running
+func (l *DefaultLogger) SetLevel(level LogLevel) {
+	l.level.Store(int32(level))
+}
+
+func (l *DefaultLogger) enabledFor(level LogLevel) bool {
+	return l.enabled && LogLevel(l.level.Load()) <= level
 }
 
 // Debug logs a debug message
 func (l *DefaultLogger) Debug(msg string, args ...any) {
-	if !l.enabled || l.level > LogLevelDebug {
+	if !l.enabledFor(LogLevelDebug) {
 		return
 	}
-	l.logger.Printf("[DEBUG] "+msg, args...)
+	l.print(LogLevelDebug, msg, args)
 }
 
 // Info logs an info message
 func (l *DefaultLogger) Info(msg string, args ...any) {
-	if !l.enabled || l.level > LogLevelInfo {
+	if !l.enabledFor(LogLevelInfo) {
 		return
 	}
-	l.logger.Printf("[INFO] "+msg, args...)
+	l.print(LogLevelInfo, msg, args)
 }
 
 // Warn logs a warning message
 func (l *DefaultLogger) Warn(msg string, args ...any) {
-	if !l.enabled || l.level > LogLevelWarn {
+	if !l.enabledFor(LogLevelWarn) {
 		return
 	}
-	l.logger.Printf("[WARN] "+msg, args...)
+	l.print(LogLevelWarn, msg, args)
 }
 
 // Error logs an error message
 func (l *DefaultLogger) Error(msg string, args ...any) {
-	if !l.enabled || l.level > LogLevelError {
+	if !l.enabledFor(LogLevelError) {
 		return
 	}
-	l.logger.Printf("[ERROR] "+msg, args...)
+	l.print(LogLevelError, msg, args)
 }
 
-// Fatal logs a fatal message and exits
+// Fatal logs a fatal message and exits, unless
+// the fatal behavior is changed by SetFatalBehavior
 func (l *DefaultLogger) Fatal(msg string, args ...any) {
-	if !l.enabled || l.level > LogLevelFatal {
+	if !l.enabledFor(LogLevelFatal) {
 		return
 	}
-	l.logger.Printf("[FATAL] "+msg, args...)
-	os.Exit(1)
+	l.print(LogLevelFatal, msg, args)
+
+	switch FatalBehavior(l.fatal.Load()) {
+	case FatalPanic:
+		panic(fmt.Sprintf(msg, args...))
+	case FatalLogOnly:
+	default:
+		os.Exit(1)
+	}
+}
+
+// SetFatalBehavior changes what Fatal does after writing the message
+func (l *DefaultLogger) SetFatalBehavior(behavior FatalBehavior) {
+	l.fatal.Store(int32(behavior))
 }
 
 // LogMode returns the current log level
@@ -125,7 +432,7 @@ func (l *DefaultLogger) LogMode() LogLevel {
 	if !l.enabled {
 		return LogLevelOff
 	}
-	return l.level
+	return LogLevel(l.level.Load())
 }
 
 // NoOpLogger is a logger that does nothing. Useful when logging is disabled.
@@ -151,6 +458,11 @@ func (l *NoOpLogger) Error(msg string, args ...any) {}
 // Fatal does nothing
 func (l *NoOpLogger) Fatal(msg string, args ...any) {}
 
+// With returns the same logger, there is nothing to attach the fields to
+func (l *NoOpLogger) With(args ...any) Logger {
+	return l
+}
+
 // LogMode returns LogLevelOff since this logger does nothing
 func (l *NoOpLogger) LogMode() LogLevel {
 	return LogLevelOff
@@ -221,6 +533,300 @@ func (l *StdLogger) LogMode() LogLevel {
 	return LogLevelDebug
 }
 
+// LevelFatal is the slog level SlogLogger uses for fatal messages,
+// slog has no such level of its own.
+const LevelFatal = slog.LevelError + 4
+
+// SlogLogger wraps log/slog's Logger to implement our Logger interface
+type SlogLogger struct {
+	logger *slog.Logger
+	fatal  atomic.Int32
+}
+
+// NewSlogLogger creates a new SlogLogger that wraps the provided slog.Logger
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+// log passes the message to slog, see SplitLogArgs.
+func (l *SlogLogger) log(level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	msg, args = SplitLogArgs(msg, args)
+	l.logger.Log(ctx, level, msg, args...)
+}
+
+// Debug logs a debug message
+func (l *SlogLogger) Debug(msg string, args ...any) {
+	l.log(slog.LevelDebug, msg, args...)
+}
+
+// Info logs an info message
+func (l *SlogLogger) Info(msg string, args ...any) {
+	l.log(slog.LevelInfo, msg, args...)
+}
+
+// Warn logs a warning message
+func (l *SlogLogger) Warn(msg string, args ...any) {
+	l.log(slog.LevelWarn, msg, args...)
+}
+
+// Error logs an error message
+func (l *SlogLogger) Error(msg string, args ...any) {
+	l.log(slog.LevelError, msg, args...)
+}
+
+// Fatal logs a fatal message at LevelFatal and exits, unless
+// the fatal behavior is changed by SetFatalBehavior
+func (l *SlogLogger) Fatal(msg string, args ...any) {
+	l.log(LevelFatal, msg, args...)
+
+	switch FatalBehavior(l.fatal.Load()) {
+	case FatalPanic:
+		msg, _ = SplitLogArgs(msg, args)
+		panic(msg)
+	case FatalLogOnly:
+	default:
+		os.Exit(1)
+	}
+}
+
+// SetFatalBehavior changes what Fatal does after writing the message
+func (l *SlogLogger) SetFatalBehavior(behavior FatalBehavior) {
+	l.fatal.Store(int32(behavior))
+}
+
+// With returns a logger which passes the key-value pairs to slog
+// along with every message, keeping the fatal behavior
+func (l *SlogLogger) With(args ...any) Logger {
+	nl := &SlogLogger{logger: l.logger.With(args...)}
+	nl.fatal.Store(l.fatal.Load())
+	return nl
+}
+
+// LogMode returns the lowest level enabled by the slog handler
+func (l *SlogLogger) LogMode() LogLevel {
+	ctx := context.Background()
+	levels := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, LevelFatal}
+	for i, level := range levels {
+		if l.logger.Enabled(ctx, level) {
+			return LogLevelDebug + LogLevel(i)
+		}
+	}
+	return LogLevelOff
+}
+
+// maxSampledKeys bounds the number of messages SampledLogger keeps
+// the counters for, the messages formatted beforehand are all unique.
+const maxSampledKeys = 1000
+
+// SampledLogger wraps a Logger limiting how many times per second
+// the same message is written, so a failing loop can't flood the log.
+// The messages are told apart by their level and format string.
+// The fatal messages are never dropped.
+type SampledLogger struct {
+	inner     Logger
+	perSecond int
+	now       func() time.Time
+
+	mu         sync.Mutex
+	samples    map[sampleKey]*sample
+	suppressed uint64
+}
+
+type sampleKey struct {
+	level LogLevel
+	msg   string
+}
+
+type sample struct {
+	window     time.Time
+	count      int
+	suppressed int
+}
+
+// NewSampledLogger creates a new SampledLogger writing the same
+// message at most perSecond times per second to the inner logger.
+func NewSampledLogger(inner Logger, perSecond int) *SampledLogger {
+	return &SampledLogger{
+		inner:     inner,
+		perSecond: max(perSecond, 1),
+		now:       time.Now,
+		samples:   make(map[sampleKey]*sample),
+	}
+}
+
+// allow counts the message and reports whether to write it. Once a new
+// window starts, it also returns the number of the messages dropped
+// in the previous one.
+func (l *SampledLogger) allow(level LogLevel, msg string) (ok bool, dropped int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := sampleKey{level, msg}
+	now := l.now()
+
+	s, found := l.samples[key]
+	if !found {
+		if len(l.samples) >= maxSampledKeys {
+			clear(l.samples)
+		}
+		s = &sample{window: now}
+		l.samples[key] = s
+	}
+
+	if now.Sub(s.window) >= time.Second {
+		dropped = s.suppressed
+		*s = sample{window: now}
+	}
+
+	if s.count >= l.perSecond {
+		s.suppressed++
+		l.suppressed++
+		return false, 0
+	}
+	s.count++
+	return true, dropped
+}
+
+func (l *SampledLogger) log(level LogLevel, write func(string, ...any), msg string, args []any) {
+	if l.inner.LogMode() > level {
+		return
+	}
+
+	ok, dropped := l.allow(level, msg)
+	if !ok {
+		return
+	}
+	if dropped > 0 {
+		write("Suppressed %d messages like %q", dropped, msg)
+	}
+	write(msg, args...)
+}
+
+// Debug logs a debug message
+func (l *SampledLogger) Debug(msg string, args ...any) {
+	l.log(LogLevelDebug, l.inner.Debug, msg, args)
+}
+
+// Info logs an info message
+func (l *SampledLogger) Info(msg string, args ...any) {
+	l.log(LogLevelInfo, l.inner.Info, msg, args)
+}
+
+// Warn logs a warning message
+func (l *SampledLogger) Warn(msg string, args ...any) {
+	l.log(LogLevelWarn, l.inner.Warn, msg, args)
+}
+
+// Error logs an error message
+func (l *SampledLogger) Error(msg string, args ...any) {
+	l.log(LogLevelError, l.inner.Error, msg, args)
+}
+
+// Fatal logs a fatal message, it's never suppressed
+func (l *SampledLogger) Fatal(msg string, args ...any) {
+	l.inner.Fatal(msg, args...)
+}
+
+// LogMode returns the level of the inner logger
+func (l *SampledLogger) LogMode() LogLevel {
+	return l.inner.LogMode()
+}
+
+// Suppressed returns the total number of the dropped messages.
+func (l *SampledLogger) Suppressed() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.suppressed
+}
+
+// namedLogger prefixes the messages with the logger name and applies
+// the level override of the name, see Bot.Logger.
+type namedLogger struct {
+	inner  Logger
+	prefix string
+	fields string
+
+	// level is the override, valid if override is set.
+	level    LogLevel
+	override bool
+}
+
+func (l *namedLogger) log(level LogLevel, write func(string, ...any), msg string, args []any) {
+	msg = l.prefix + msg + l.fields
+	if !l.override {
+		write(msg, args...)
+		return
+	}
+	if level < l.level {
+		return
+	}
+
+	// The inner logger would drop the messages below its own level.
+	if level < l.inner.LogMode() {
+		if dl, ok := l.inner.(*DefaultLogger); ok {
+			dl.printAt(level, msg, args)
+		}
+		return
+	}
+	write(msg, args...)
+}
+
+// Debug logs a debug message
+func (l *namedLogger) Debug(msg string, args ...any) {
+	l.log(LogLevelDebug, l.inner.Debug, msg, args)
+}
+
+// Info logs an info message
+func (l *namedLogger) Info(msg string, args ...any) {
+	l.log(LogLevelInfo, l.inner.Info, msg, args)
+}
+
+// Warn logs a warning message
+func (l *namedLogger) Warn(msg string, args ...any) {
+	l.log(LogLevelWarn, l.inner.Warn, msg, args)
+}
+
+// Error logs an error message
+func (l *namedLogger) Error(msg string, args ...any) {
+	l.log(LogLevelError, l.inner.Error, msg, args)
+}
+
+// Fatal logs a fatal message, the override doesn't apply to it
+func (l *namedLogger) Fatal(msg string, args ...any) {
+	l.inner.Fatal(l.prefix+msg+l.fields, args...)
+}
+
+// With returns a logger which writes the key-value pairs along
+// with every message, keeping the name
+func (l *namedLogger) With(args ...any) Logger {
+	child := *l
+	if fl, ok := l.inner.(FieldLogger); ok {
+		child.inner = fl.With(args...)
+	} else {
+		child.fields += formatFields(args)
+	}
+	return &child
+}
+
+// LogMode returns the level override, if any, or the inner level
+func (l *namedLogger) LogMode() LogLevel {
+	mode := l.inner.LogMode()
+	if !l.override {
+		return mode
+	}
+	if _, ok := l.inner.(*DefaultLogger); ok && mode != LogLevelOff {
+		return l.level
+	}
+	return max(l.level, mode)
+}
+
 // NewLogger creates a logger based on the provided LogConfig
 func NewLogger(config LogConfig) Logger {
 	// Enable has the highest priority
@@ -234,5 +840,45 @@ func NewLogger(config LogConfig) Logger {
 	}
 
 	// Create default logger with configuration
-	return NewDefaultLogger(config.Level, config.Prefix)
+	var l *DefaultLogger
+	if config.Format == LogFormatJSON {
+		l = NewDefaultJSONLogger(config.Level, config.Prefix)
+	} else {
+		l = NewDefaultLogger(config.Level, config.Prefix)
+	}
+	if len(config.Sinks) > 0 {
+		l.logger.SetOutput(io.MultiWriter(config.Sinks...))
+	}
+	l.SetFatalBehavior(config.FatalBehavior)
+	return l
+}
+
+// SetLogLevel changes the minimum level of the bot logger at runtime, e.g.
+// from an admin command. It works with the loggers having the SetLevel
+// method, such as DefaultLogger.
+func (b *Bot) SetLogLevel(level LogLevel) error {
+	l, ok := b.logger.(interface{ SetLevel(LogLevel) })
+	if !ok {
+		return fmt.Errorf("telebot: logger %T doesn't support changing the level", b.logger)
+	}
+	l.SetLevel(level)
+	return nil
+}
+
+// Logger returns the bot logger for the named part of the bot, such as
+// a module. Its messages are prefixed with "[name] " and the level can
+// be overridden in LogConfig.Levels. The framework uses the names
+// "poller", "webhook" and "handler:<endpoint>" for the handler
+// loggers returned by Context.Logger.
+func (b *Bot) Logger(name string) Logger {
+	l := &namedLogger{
+		inner:  b.logger,
+		prefix: "[" + strings.ReplaceAll(name, "%", "%%") + "] ",
+	}
+	l.level, l.override = b.logLevels[name]
+
+	if b.redactor == nil {
+		return l
+	}
+	return &redactingLogger{Logger: l, r: b.redactor}
 }
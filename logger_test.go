@@ -2,10 +2,21 @@ package telebot
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type CustomTestLogger struct {
@@ -59,8 +70,8 @@ func NewLevelTestLogger(level LogLevel) *LevelTestLogger {
 	defaultLogger := &DefaultLogger{
 		logger:  log.New(buffer, "[LEVEL] ", 0),
 		enabled: true,
-		level:   level,
 	}
+	defaultLogger.SetLevel(level)
 	return &LevelTestLogger{
 		DefaultLogger: defaultLogger,
 		buffer:        buffer,
@@ -274,3 +285,353 @@ func TestLogConfigNilUsesNoOpLogger(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, &NoOpLogger{}, bot.logger)
 }
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := NewSlogLogger(slog.New(handler))
+	assert.Equal(t, LogLevelInfo, logger.LogMode())
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	logger.Info("update %d received", 42)
+	assert.Equal(t, "level=INFO msg=\"update 42 received\"\n", buf.String())
+	buf.Reset()
+
+	logger.Warn("slow handler", "chat", int64(7), "took", "2s")
+	assert.Equal(t, "level=WARN msg=\"slow handler\" chat=7 took=2s\n", buf.String())
+	buf.Reset()
+
+	logger.Info("disk 90% full", "host", "db1")
+	assert.Equal(t, "level=INFO msg=\"disk 90% full\" host=db1\n", buf.String())
+	buf.Reset()
+
+	logger.SetFatalBehavior(FatalPanic)
+	assert.PanicsWithValue(t, "stopped: 1", func() {
+		logger.With("chat", 7).Fatal("stopped: %d", 1)
+	})
+	logger.SetFatalBehavior(FatalLogOnly)
+	assert.NotPanics(t, func() {
+		logger.Fatal("stopped", "code", 2)
+	})
+	assert.Equal(t, "level=ERROR+4 msg=\"stopped: 1\" chat=7\nlevel=ERROR+4 msg=stopped code=2\n", buf.String())
+
+	off := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelFatal + 1})))
+	assert.Equal(t, LogLevelOff, off.LogMode())
+}
+
+func TestSplitLogArgs(t *testing.T) {
+	for _, tt := range []struct {
+		msg  string
+		args []any
+		want string
+		rest []any
+	}{
+		{"update %d received", []any{42}, "update 42 received", nil},
+		{"%s: %v%%", []any{"rate", 90}, "rate: 90%", nil},
+		{"%-*d|", []any{4, 7}, "7   |", nil},
+		{"%[2]s %[1]s", []any{"a", "b"}, "b a", nil},
+		{"slow handler", []any{"chat", 7}, "slow handler", []any{"chat", 7}},
+		{"disk 90% full", []any{"host", "db1"}, "disk 90% full", []any{"host", "db1"}},
+		{"no args %d", nil, "no args %d", nil},
+	} {
+		msg, rest := SplitLogArgs(tt.msg, tt.args)
+		assert.Equal(t, tt.want, msg)
+		assert.Equal(t, tt.rest, rest)
+	}
+}
+
+func TestLoggerWith(t *testing.T) {
+	custom := NewCustomTestLogger()
+	logger := LoggerWith(custom, "chat_id", int64(42), "handler", "/start")
+	logger = LoggerWith(logger, "rate", "100%")
+
+	logger.Info("handled in %dms", 5)
+	assert.Equal(t, "[CUSTOM] [INFO] handled in 5ms chat_id=42 handler=/start rate=100%\n", custom.GetOutput())
+	assert.Same(t, custom, LoggerWith(custom))
+
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	LoggerWith(NewSlogLogger(slog.New(handler)), "chat_id", int64(42)).Info("handled")
+	assert.Equal(t, "level=INFO msg=handled chat_id=42\n", buf.String())
+
+	noop := NewNoOpLogger()
+	assert.Same(t, noop, LoggerWith(noop, "chat_id", 42))
+}
+
+func TestContextLoggerCorrelation(t *testing.T) {
+	custom := NewCustomTestLogger()
+	bot, err := NewBot(Settings{
+		Offline:       true,
+		CorrelateLogs: true,
+		Log:           &LogConfig{Enable: true, Logger: custom},
+	})
+	assert.NoError(t, err)
+
+	ctx := NewContext(bot, Update{ID: 10, Message: &Message{
+		Chat:   &Chat{ID: 42},
+		Sender: &User{ID: 7},
+	}})
+
+	logger := ctx.Logger()
+	assert.Same(t, logger, ctx.Logger())
+
+	logger.Info("first")
+	ctx.Logger().Info("second")
+
+	lines := strings.Split(strings.TrimSpace(custom.GetOutput()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, `^\[CUSTOM\] \[INFO\] first correlation_id=[0-9a-f]{16} update_id=10 chat_id=42 sender_id=7$`, lines[0])
+
+	id := func(line string) string {
+		return strings.Fields(line)[3]
+	}
+	assert.Equal(t, id(lines[0]), id(lines[1]))
+
+	other := NewContext(bot, Update{ID: 11})
+	other.Logger().Info("third")
+	lines = strings.Split(strings.TrimSpace(custom.GetOutput()), "\n")
+	assert.NotEqual(t, id(lines[0]), id(lines[2]))
+}
+
+func TestBotSetLogLevel(t *testing.T) {
+	level := NewLevelTestLogger(LogLevelWarn)
+	bot, err := NewBot(Settings{Offline: true, Log: &LogConfig{Enable: true, Logger: level}})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			bot.logger.Debug("racing")
+		}
+	}()
+	require.NoError(t, bot.SetLogLevel(LogLevelDebug))
+	wg.Wait()
+
+	assert.Equal(t, LogLevelDebug, level.LogMode())
+	level.buffer.Reset()
+	bot.logger.Debug("now visible")
+	assert.Contains(t, level.GetOutput(), "now visible")
+
+	bot, err = NewBot(Settings{Offline: true, Log: &LogConfig{Enable: true, Logger: NewCustomTestLogger()}})
+	require.NoError(t, err)
+	assert.Error(t, bot.SetLogLevel(LogLevelDebug))
+}
+
+func TestDefaultJSONLogger(t *testing.T) {
+	logger := NewDefaultJSONLogger(LogLevelInfo, "bot")
+	assert.IsType(t, logger, NewLogger(LogConfig{Enable: true, Format: LogFormatJSON}))
+
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	logger.Error("update %d failed: %v", 42, errors.New("timeout"))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	_, err := time.Parse(time.RFC3339Nano, entry["time"].(string))
+	assert.NoError(t, err)
+	delete(entry, "time")
+
+	assert.Equal(t, map[string]any{
+		"level":  "ERROR",
+		"prefix": "bot",
+		"msg":    "update 42 failed: timeout",
+		"args":   []any{float64(42), "timeout"},
+	}, entry)
+
+	buf.Reset()
+	logger.Info("channel %v", make(chan int))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Len(t, entry["args"], 1)
+}
+
+func TestSampledLogger(t *testing.T) {
+	custom := NewCustomTestLogger()
+	logger := NewSampledLogger(custom, 2)
+
+	now := time.Unix(0, 0)
+	logger.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("getUpdates failed: %v", i)
+	}
+	logger.Error("getUpdates failed: %v", 5)
+	assert.Equal(t, uint64(3), logger.Suppressed())
+
+	now = now.Add(time.Second)
+	logger.Warn("getUpdates failed: %v", 6)
+
+	assert.Equal(t, "[CUSTOM] [WARN] getUpdates failed: 0\n"+
+		"[CUSTOM] [WARN] getUpdates failed: 1\n"+
+		"[CUSTOM] [ERROR] getUpdates failed: 5\n"+
+		"[CUSTOM] [WARN] Suppressed 3 messages like \"getUpdates failed: %v\"\n"+
+		"[CUSTOM] [WARN] getUpdates failed: 6\n", custom.GetOutput())
+}
+
+func TestLogRedaction(t *testing.T) {
+	custom := NewCustomTestLogger()
+	bot, err := NewBot(Settings{
+		Token:   "123:secret",
+		Offline: true,
+		Poller:  &Webhook{SecretToken: "hook-secret"},
+		Log: &LogConfig{
+			Enable:  true,
+			Logger:  custom,
+			Secrets: []string{"extra"},
+		},
+	})
+	require.NoError(t, err)
+
+	bot.redactor.add("provider-token")
+	err = errors.New(`Post "https://api.telegram.org/bot123:secret/getMe": EOF`)
+	bot.output.Warn("failed: %v, %s, %s, %s", err, "hook-secret", "provider-token", "extra")
+	assert.Equal(t, "[CUSTOM] [WARN] failed: Post \"https://api.telegram.org/bot<redacted>/getMe\": EOF, "+
+		"<redacted>, <redacted>, <redacted>\n", custom.GetOutput())
+
+	custom = NewCustomTestLogger()
+	bot, err = NewBot(Settings{
+		Token:   "123:secret",
+		Offline: true,
+		Log: &LogConfig{
+			Enable:           true,
+			Logger:           custom,
+			DisableRedaction: true,
+		},
+	})
+	require.NoError(t, err)
+
+	bot.output.Warn("token %s", "123:secret")
+	assert.Equal(t, "[CUSTOM] [WARN] token 123:secret\n", custom.GetOutput())
+}
+
+func TestNamedLoggers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := NewDefaultLogger(LogLevelInfo, "")
+	base.logger = log.New(buf, "", 0)
+
+	bot, err := NewBot(Settings{
+		Offline:     true,
+		Synchronous: true,
+		Log: &LogConfig{
+			Enable: true,
+			Logger: base,
+			Levels: map[string]LogLevel{
+				"handler:/debug": LogLevelDebug,
+				"quiet":          LogLevelError,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	bot.Logger("module").Debug("hidden")
+	bot.Logger("module").Info("shown %d", 1)
+	bot.Logger("quiet").Warn("hidden")
+	bot.Logger("quiet").Error("shown %d", 2)
+
+	bot.Handle("/debug", func(c Context) error {
+		c.Logger().Debug("shown %d", 3)
+		return nil
+	})
+	bot.Handle("/other", func(c Context) error {
+		c.Logger().Debug("hidden")
+		return nil
+	})
+	bot.ProcessUpdate(Update{Message: &Message{Text: "/debug"}})
+	bot.ProcessUpdate(Update{Message: &Message{Text: "/other"}})
+
+	assert.Equal(t, "[INFO] [module] shown 1\n"+
+		"[ERROR] [quiet] shown 2\n"+
+		"[DEBUG] [handler:/debug] shown 3\n", buf.String())
+	assert.Equal(t, LogLevelDebug, bot.Logger("handler:/debug").LogMode())
+	assert.Equal(t, LogLevelInfo, bot.Logger("module").LogMode())
+}
+
+func TestDefaultLoggerFatalBehavior(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewLogger(LogConfig{Enable: true, FatalBehavior: FatalPanic}).(*DefaultLogger)
+	l.logger = log.New(buf, "", 0)
+
+	assert.PanicsWithValue(t, "stopped: 1", func() {
+		l.Fatal("stopped: %d", 1)
+	})
+
+	l.SetFatalBehavior(FatalLogOnly)
+	assert.NotPanics(t, func() {
+		l.Fatal("stopped: %d", 2)
+	})
+	assert.Equal(t, "[FATAL] stopped: 1\n[FATAL] stopped: 2\n", buf.String())
+}
+
+func TestLogSinks(t *testing.T) {
+	var first, second bytes.Buffer
+	l := NewLogger(LogConfig{Enable: true, Sinks: []io.Writer{&first, &second}})
+
+	l.Info("to both")
+	assert.Contains(t, first.String(), "[INFO] to both")
+	assert.Equal(t, first.String(), second.String())
+}
+
+func TestRotatingFile(t *testing.T) {
+	// The unrelated files next to it are kept, and
+	// the glob metacharacters have no special meaning.
+	path := filepath.Join(t.TempDir(), "bot[1].log")
+	other := path + ".yaml"
+	require.NoError(t, os.WriteFile(other, nil, 0o644))
+
+	f := &RotatingFile{Path: path, MaxSize: 10, MaxBackups: 1}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+
+	backups := f.backups()
+	require.Len(t, backups, 1)
+	assert.FileExists(t, other)
+
+	data, err = os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(data))
+}
+
+func TestLoggerWithContext(t *testing.T) {
+	custom := NewCustomTestLogger()
+
+	ctx := context.WithValue(context.Background(), TraceIDKey, "abc")
+	LoggerWithContext(custom, ctx).Info("traced")
+
+	ctx = context.WithValue(ctx, TenantIDKey, 42)
+	LoggerWithContext(custom, ctx).Info("tenant")
+
+	assert.Same(t, custom, LoggerWithContext(custom, context.Background()))
+	assert.Equal(t, "[CUSTOM] [INFO] traced trace_id=abc\n"+
+		"[CUSTOM] [INFO] tenant trace_id=abc tenant_id=42\n", custom.GetOutput())
+}
@@ -0,0 +1,79 @@
+package telebot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginWidgetMaxAge is the default freshness window of the
+// Login Widget data, see VerifyLoginWidget.
+const LoginWidgetMaxAge = 24 * time.Hour
+
+// VerifyLoginWidget checks the data received from the Telegram Login
+// Widget, e.g. the query parameters of the redirect to the website,
+// using the bot token. The data must be no older than LoginWidgetMaxAge.
+//
+// Returns the authenticated user on success.
+//
+// See https://core.telegram.org/widgets/login#checking-authorization
+func VerifyLoginWidget(data map[string]string, token string) (*User, error) {
+	return VerifyLoginWidgetAge(data, token, LoginWidgetMaxAge)
+}
+
+// VerifyLoginWidgetAge works like VerifyLoginWidget, but accepts the data
+// no older than maxAge. Zero maxAge disables the auth_date check.
+func VerifyLoginWidgetAge(data map[string]string, token string, maxAge time.Duration) (*User, error) {
+	return verifyLoginWidget(data, token, maxAge, time.Now())
+}
+
+func verifyLoginWidget(data map[string]string, token string, maxAge time.Duration, now time.Time) (*User, error) {
+	hash, err := hex.DecodeString(data["hash"])
+	if err != nil || len(hash) != sha256.Size {
+		return nil, ErrLoginHash
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if k != "hash" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + data[k]
+	}
+
+	secret := sha256.Sum256([]byte(token))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	if !hmac.Equal(mac.Sum(nil), hash) {
+		return nil, ErrLoginHash
+	}
+
+	authDate, err := strconv.ParseInt(data["auth_date"], 10, 64)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if maxAge > 0 && now.Sub(time.Unix(authDate, 0)) > maxAge {
+		return nil, ErrLoginExpired
+	}
+
+	id, err := strconv.ParseInt(data["id"], 10, 64)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &User{
+		ID:        id,
+		FirstName: data["first_name"],
+		LastName:  data["last_name"],
+		Username:  data["username"],
+	}, nil
+}
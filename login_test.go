@@ -0,0 +1,57 @@
+package telebot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signLogin(data map[string]string, token string) {
+	secret := sha256.Sum256([]byte(token))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte("auth_date=" + data["auth_date"] +
+		"\nfirst_name=" + data["first_name"] +
+		"\nid=" + data["id"] +
+		"\nusername=" + data["username"]))
+	data["hash"] = hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyLoginWidget(t *testing.T) {
+	const token = "123:secret"
+	now := time.Unix(1700000000, 0)
+
+	data := map[string]string{
+		"id":         "42",
+		"first_name": "Ann",
+		"username":   "ann",
+		"auth_date":  strconv.FormatInt(now.Add(-time.Hour).Unix(), 10),
+	}
+	signLogin(data, token)
+
+	user, err := verifyLoginWidget(data, token, LoginWidgetMaxAge, now)
+	require.NoError(t, err)
+	assert.Equal(t, &User{ID: 42, FirstName: "Ann", Username: "ann"}, user)
+
+	_, err = verifyLoginWidget(data, "123:other", LoginWidgetMaxAge, now)
+	assert.ErrorIs(t, err, ErrLoginHash)
+
+	_, err = verifyLoginWidget(data, token, time.Minute, now)
+	assert.ErrorIs(t, err, ErrLoginExpired)
+
+	_, err = verifyLoginWidget(data, token, 0, now.Add(1000*time.Hour))
+	assert.NoError(t, err)
+
+	data["first_name"] = "Bob"
+	_, err = verifyLoginWidget(data, token, LoginWidgetMaxAge, now)
+	assert.ErrorIs(t, err, ErrLoginHash)
+
+	delete(data, "hash")
+	_, err = verifyLoginWidget(data, token, LoginWidgetMaxAge, now)
+	assert.ErrorIs(t, err, ErrLoginHash)
+}
@@ -2,8 +2,11 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // ReplyMarkup controls two convenient options for bot-user communications
@@ -80,6 +83,18 @@ func (r *ReplyMarkup) copy() *ReplyMarkup {
 	return &cp
 }
 
+// validate checks the inline buttons.
+func (r *ReplyMarkup) validate() error {
+	for _, row := range r.InlineKeyboard {
+		for i := range row {
+			if err := row[i].validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Btn is a constructor button, which will later become either a reply, or an inline button.
 type Btn struct {
 	Unique          string          `json:"unique,omitempty"`
@@ -95,6 +110,9 @@ type Btn struct {
 	Poll            PollType        `json:"request_poll,omitempty"`
 	User            *ReplyRecipient `json:"request_user,omitempty"`
 	Chat            *ReplyRecipient `json:"request_chat,omitempty"`
+	CopyText        *CopyTextButton `json:"copy_text,omitempty"`
+
+	state any
 }
 
 // Row represents an array of buttons, a row.
@@ -158,6 +176,120 @@ func (r *ReplyMarkup) Reply(rows ...Row) {
 	r.ReplyKeyboard = replyKeys
 }
 
+// ReplyGrid builds a resized one-time reply keyboard of text buttons,
+// putting up to columns buttons in a row. The last row holds the rest.
+// Returns nil if there are no labels.
+//
+// `ReplyGrid(2, "Yes", "No", "Maybe") -> [[Yes, No], [Maybe]]`
+func ReplyGrid(columns int, labels ...string) *ReplyMarkup {
+	if len(labels) == 0 {
+		return nil
+	}
+	if columns < 1 {
+		columns = 1
+	}
+
+	rows := make([][]ReplyButton, 0, (columns-1+len(labels))/columns)
+	for i := 0; i < len(labels); i += columns {
+		row := make([]ReplyButton, 0, columns)
+		for _, label := range labels[i:min(i+columns, len(labels))] {
+			row = append(row, ReplyButton{Text: label})
+		}
+		rows = append(rows, row)
+	}
+
+	return &ReplyMarkup{
+		ReplyKeyboard:   rows,
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: true,
+	}
+}
+
+// InlineGrid builds an inline keyboard, putting up to columns buttons
+// in a row. The last row holds the rest. Returns nil if there are
+// no buttons.
+func InlineGrid(columns int, buttons ...InlineButton) *ReplyMarkup {
+	if len(buttons) == 0 {
+		return nil
+	}
+	if columns < 1 {
+		columns = 1
+	}
+
+	rows := make([][]InlineButton, 0, (columns-1+len(buttons))/columns)
+	for i := 0; i < len(buttons); i += columns {
+		row := make([]InlineButton, 0, columns)
+		row = append(row, buttons[i:min(i+columns, len(buttons))]...)
+		rows = append(rows, row)
+	}
+
+	return &ReplyMarkup{InlineKeyboard: rows}
+}
+
+// Inline keyboard limits, enforced by Telegram per message.
+const (
+	MaxInlineRowButtons = 8
+	MaxInlineButtons    = 100
+)
+
+// InlinePages splits the buttons into inline keyboards, one per message
+// or page, of up to perPage buttons in rows of the given columns. When
+// there is more than one page, each keyboard gets a navigation row
+// with the previous page, page counter and next page buttons. They are
+// data buttons with the passed unique, and their data is the target
+// page index, so a single handler can switch between the pages:
+//
+//	pages, err := tele.InlinePages("page", 3, 30, countries...)
+//	...
+//	b.Handle(&tele.InlineButton{Unique: "page"}, func(c tele.Context) error {
+//		i, _ := strconv.Atoi(c.Data())
+//		return c.Edit(pages[i])
+//	})
+//
+// Returns an error if the keyboards would exceed the inline keyboard
+// limits. Returns nil if there are no buttons.
+func InlinePages(unique string, columns, perPage int, buttons ...InlineButton) ([]*ReplyMarkup, error) {
+	if len(buttons) == 0 {
+		return nil, nil
+	}
+	if columns < 1 || columns > MaxInlineRowButtons {
+		return nil, fmt.Errorf("telebot: inline row must have 1 to %d buttons, got %d", MaxInlineRowButtons, columns)
+	}
+	if perPage < 1 || perPage > MaxInlineButtons-3 {
+		return nil, fmt.Errorf("telebot: inline page must have 1 to %d buttons, got %d", MaxInlineButtons-3, perPage)
+	}
+	if len(buttons) > perPage && unique == "" {
+		return nil, errors.New("telebot: inline pages need a unique for the navigation")
+	}
+
+	total := (perPage - 1 + len(buttons)) / perPage
+	pages := make([]*ReplyMarkup, 0, total)
+	for i := 0; i < total; i++ {
+		page := InlineGrid(columns, buttons[i*perPage:min((i+1)*perPage, len(buttons))]...)
+		if total > 1 {
+			page.InlineKeyboard = append(page.InlineKeyboard, pageNavigation(unique, i, total))
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+func pageNavigation(unique string, page, total int) []InlineButton {
+	btn := func(text string, page int) InlineButton {
+		return InlineButton{Unique: unique, Text: text, Data: strconv.Itoa(page)}
+	}
+
+	row := make([]InlineButton, 0, 3)
+	if page > 0 {
+		row = append(row, btn("«", page-1))
+	}
+	row = append(row, btn(fmt.Sprintf("%d/%d", page+1, total), page))
+	if page < total-1 {
+		row = append(row, btn("»", page+1))
+	}
+	return row
+}
+
 func (r *ReplyMarkup) Text(text string) Btn {
 	return Btn{Text: text}
 }
@@ -170,6 +302,14 @@ func (r *ReplyMarkup) Data(text, unique string, data ...string) Btn {
 	}
 }
 
+// DataPattern returns the callback button with the data made of the
+// pattern filled with the args, see Bot.HandleCallback and FillPattern:
+//
+//	r.DataPattern("Buy", "item:{id}:{action}", item.ID, "buy")
+func (r *ReplyMarkup) DataPattern(text, pattern string, args ...any) Btn {
+	return Btn{Text: text, Data: FillPattern(pattern, args...)}
+}
+
 func (r *ReplyMarkup) URL(text, url string) Btn {
 	return Btn{Text: text, URL: url}
 }
@@ -210,6 +350,10 @@ func (r *ReplyMarkup) WebApp(text string, app *WebApp) Btn {
 	return Btn{Text: text, WebApp: app}
 }
 
+func (r *ReplyMarkup) CopyText(text, copyText string) Btn {
+	return Btn{Text: text, CopyText: &CopyTextButton{Text: copyText}}
+}
+
 // ReplyButton represents a button displayed in reply-keyboard.
 //
 // Set either Contact or Location to true in order to request
@@ -297,15 +441,43 @@ type InlineButton struct {
 	WebApp                *WebApp            `json:"web_app,omitempty"`
 	CallbackGame          *CallbackGame      `json:"callback_game,omitempty"`
 	Pay                   bool               `json:"pay,omitempty"`
+	CopyText              *CopyTextButton    `json:"copy_text,omitempty"`
+
+	// state is put to the CallbackStore on send, see Btn.WithState.
+	state any
+}
+
+// CopyTextButton describes the inline button that copies
+// the text to the clipboard, up to MaxCopyText characters.
+type CopyTextButton struct {
+	Text string `json:"text"`
+}
+
+// MaxCopyText is the maximum length of the text a button copies.
+const MaxCopyText = 256
+
+// validate checks that the copy text button has no other actions.
+func (t *InlineButton) validate() error {
+	if t.CopyText == nil {
+		return nil
+	}
+	if n := utf8.RuneCountInString(t.CopyText.Text); n < 1 || n > MaxCopyText {
+		return fmt.Errorf("telebot: copy text of button %q must be 1-%d characters, got %d", t.Text, MaxCopyText, n)
+	}
+	if t.Unique != "" || t.URL != "" || t.Data != "" || t.InlineQuery != "" || t.InlineQueryChat != "" ||
+		t.InlineQueryChosenChat != nil || t.Login != nil || t.WebApp != nil || t.CallbackGame != nil || t.Pay {
+		return fmt.Errorf("telebot: copy text button %q must not have other actions", t.Text)
+	}
+	return nil
 }
 
 // MarshalJSON implements json.Marshaler interface.
-// It needed to avoid InlineQueryChat and Login or WebApp fields conflict.
-// If you have Login or WebApp field in your button, InlineQueryChat must be skipped.
+// It needed to avoid InlineQueryChat and Login, WebApp or CopyText fields conflict.
+// If you have Login, WebApp or CopyText field in your button, InlineQueryChat must be skipped.
 func (t *InlineButton) MarshalJSON() ([]byte, error) {
 	type IB InlineButton
 
-	if t.Login != nil || t.WebApp != nil {
+	if t.Login != nil || t.WebApp != nil || t.CopyText != nil {
 		return json.Marshal(struct {
 			IB
 			InlineQueryChat string `json:"switch_inline_query_current_chat,omitempty"`
@@ -355,9 +527,22 @@ func (b Btn) Inline() *InlineButton {
 		InlineQueryChat: b.InlineQueryChat,
 		Login:           b.Login,
 		WebApp:          b.WebApp,
+		CopyText:        b.CopyText,
+		state:           b.state,
 	}
 }
 
+// WithState returns a copy of the button carrying the state, which is
+// kept in the bot's CallbackStore instead of the callback data. The state
+// is marshalled to JSON on send and can be read back in the callback
+// handler with Context.CallbackState.
+//
+// The default store is in-memory, so the states are lost on restart.
+func (b Btn) WithState(v any) Btn {
+	b.state = v
+	return b
+}
+
 // Login represents a parameter of the inline keyboard button
 // used to automatically authorize a user. Serves as a great replacement
 // for the Telegram Login Widget when the user is coming from Telegram.
@@ -1,6 +1,9 @@
 package telebot
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,6 +26,31 @@ func TestBtn(t *testing.T) {
 	assert.Equal(t, &InlineButton{Text: "T", InlineQueryChat: "q"}, r.QueryChat("T", "q").Inline())
 	assert.Equal(t, &InlineButton{Text: "T", Login: &Login{Text: "T"}}, r.Login("T", &Login{Text: "T"}).Inline())
 	assert.Equal(t, &InlineButton{Text: "T", WebApp: &WebApp{URL: "url"}}, r.WebApp("T", &WebApp{URL: "url"}).Inline())
+	assert.Equal(t, &InlineButton{Text: "T", CopyText: &CopyTextButton{Text: "c"}}, r.CopyText("T", "c").Inline())
+}
+
+func TestCopyTextButton(t *testing.T) {
+	r := &ReplyMarkup{}
+	r.Inline(r.Row(r.CopyText("Copy", "COUPON")))
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"inline_keyboard":[[{"text":"Copy","copy_text":{"text":"COUPON"}}]]}`, string(data))
+	assert.NoError(t, r.validate())
+
+	long := &InlineButton{Text: "Copy", CopyText: &CopyTextButton{Text: strings.Repeat("я", MaxCopyText+1)}}
+	assert.Error(t, long.validate())
+	empty := &InlineButton{Text: "Copy", CopyText: &CopyTextButton{}}
+	assert.Error(t, empty.validate())
+	mixed := &InlineButton{Text: "Copy", URL: "url", CopyText: &CopyTextButton{Text: "COUPON"}}
+	assert.Error(t, mixed.validate())
+
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+	_, err = b.Send(&Chat{ID: 1}, "text", &ReplyMarkup{InlineKeyboard: [][]InlineButton{{*mixed}}})
+	assert.ErrorContains(t, err, "must not have other actions")
+	_, err = b.EditReplyMarkup(&Message{ID: 1, Chat: &Chat{ID: 1}}, &ReplyMarkup{InlineKeyboard: [][]InlineButton{{*long}}})
+	assert.ErrorContains(t, err, "must be 1-256 characters")
 }
 
 func TestOptions(t *testing.T) {
@@ -63,3 +91,71 @@ func TestOptions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte(`{"type":"quiz"}`), data)
 }
+
+func TestGrid(t *testing.T) {
+	assert.Nil(t, ReplyGrid(2))
+	assert.Nil(t, InlineGrid(2))
+
+	r := ReplyGrid(2, "Yes", "No", "Maybe")
+	assert.Equal(t, [][]ReplyButton{
+		{{Text: "Yes"}, {Text: "No"}},
+		{{Text: "Maybe"}},
+	}, r.ReplyKeyboard)
+	assert.True(t, r.ResizeKeyboard)
+	assert.True(t, r.OneTimeKeyboard)
+
+	assert.Len(t, ReplyGrid(0, "Yes", "No").ReplyKeyboard, 2)
+
+	i := InlineGrid(3,
+		InlineButton{Text: "1"}, InlineButton{Text: "2"},
+		InlineButton{Text: "3"}, InlineButton{Text: "4"},
+	)
+	assert.Equal(t, [][]InlineButton{
+		{{Text: "1"}, {Text: "2"}, {Text: "3"}},
+		{{Text: "4"}},
+	}, i.InlineKeyboard)
+}
+
+func TestInlinePages(t *testing.T) {
+	pages, err := InlinePages("page", 2, 10)
+	require.NoError(t, err)
+	assert.Nil(t, pages)
+
+	buttons := make([]InlineButton, 25)
+	for i := range buttons {
+		buttons[i] = InlineButton{Text: strconv.Itoa(i), Data: strconv.Itoa(i)}
+	}
+
+	pages, err = InlinePages("page", 4, 10, buttons...)
+	require.NoError(t, err)
+	require.Len(t, pages, 3)
+
+	assert.Len(t, pages[0].InlineKeyboard, 4)
+	assert.Equal(t, []InlineButton{
+		{Unique: "page", Text: "1/3", Data: "0"},
+		{Unique: "page", Text: "»", Data: "1"},
+	}, pages[0].InlineKeyboard[3])
+	assert.Equal(t, []InlineButton{
+		{Unique: "page", Text: "«", Data: "0"},
+		{Unique: "page", Text: "2/3", Data: "1"},
+		{Unique: "page", Text: "»", Data: "2"},
+	}, pages[1].InlineKeyboard[3])
+	assert.Equal(t, [][]InlineButton{
+		{buttons[20], buttons[21], buttons[22], buttons[23]},
+		{buttons[24]},
+		{{Unique: "page", Text: "«", Data: "1"}, {Unique: "page", Text: "3/3", Data: "2"}},
+	}, pages[2].InlineKeyboard)
+
+	// A single page needs no navigation
+	pages, err = InlinePages("", 8, 97, buttons...)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Len(t, pages[0].InlineKeyboard, 4)
+
+	_, err = InlinePages("page", 9, 10, buttons...)
+	assert.Error(t, err)
+	_, err = InlinePages("page", 8, 98, buttons...)
+	assert.Error(t, err)
+	_, err = InlinePages("", 8, 10, buttons...)
+	assert.Error(t, err)
+}
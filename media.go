@@ -1,8 +1,17 @@
 package telebot
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
 )
 
 // Media is a generic type for all kinds of media that includes File.
@@ -14,6 +23,84 @@ type Media interface {
 	MediaFile() *File
 }
 
+// MediaType is the kind of media Bot.Send sends a plain File as.
+// Pass it along with the file to skip the detection.
+type MediaType string
+
+const (
+	MediaPhoto     MediaType = "photo"
+	MediaVideo     MediaType = "video"
+	MediaAudio     MediaType = "audio"
+	MediaAnimation MediaType = "animation"
+	MediaDocument  MediaType = "document"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// DetectMediaType sniffs the media type from the first 512 bytes
+// read from r. Content that can't be sent as a photo, video, audio
+// or animation is detected as MediaDocument.
+func DetectMediaType(r io.Reader) MediaType {
+	head := make([]byte, sniffLen)
+	n, _ := io.ReadFull(r, head)
+	return mediaTypeOf(http.DetectContentType(head[:n]))
+}
+
+func mediaTypeOf(contentType string) MediaType {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp", "image/bmp":
+		return MediaPhoto
+	case "image/gif":
+		return MediaAnimation
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "video/"):
+		return MediaVideo
+	case strings.HasPrefix(contentType, "audio/"):
+		return MediaAudio
+	default:
+		return MediaDocument
+	}
+}
+
+// detectMediaType sniffs the content of the local or reader-backed
+// file, falling back to the file name extension. The reader is
+// replaced, so that the sniffed bytes are still uploaded.
+func (f *File) detectMediaType() MediaType {
+	var (
+		head = make([]byte, sniffLen)
+		name string
+		n    int
+	)
+
+	switch {
+	case f.FileReader != nil:
+		n, _ = io.ReadFull(f.FileReader, head)
+		f.FileReader = io.MultiReader(bytes.NewReader(head[:n]), f.FileReader)
+		name = f.fileName
+	case f.FileLocal != "":
+		if file, err := os.Open(f.FileLocal); err == nil {
+			n, _ = io.ReadFull(file, head)
+			file.Close()
+		}
+		name = f.FileLocal
+	case f.FileURL != "":
+		if u, err := url.Parse(f.FileURL); err == nil {
+			name = u.Path
+		}
+	}
+
+	if n > 0 {
+		if contentType := http.DetectContentType(head[:n]); contentType != "application/octet-stream" {
+			return mediaTypeOf(contentType)
+		}
+	}
+	return mediaTypeOf(mime.TypeByExtension(path.Ext(name)))
+}
+
 // InputMedia represents a composite InputMedia struct that is
 // used by Telebot in sending and editing media methods.
 type InputMedia struct {
@@ -65,6 +152,32 @@ func (a Album) SetCaption(caption string) {
 	}
 }
 
+// validate checks the album fits the media group constraints:
+// it must consist of 2-10 items, and documents and audio files
+// can only be grouped with the items of the same type.
+func (a Album) validate() error {
+	if len(a) < 2 || len(a) > 10 {
+		return fmt.Errorf("telebot: album must include 2-10 items, got %d", len(a))
+	}
+
+	first := a[0].MediaType()
+	for i, x := range a {
+		switch kind := x.MediaType(); kind {
+		case "photo", "video":
+			if first == "audio" || first == "document" {
+				return fmt.Errorf("telebot: album entry #%d can't be grouped with %s", i, first)
+			}
+		case "audio", "document":
+			if kind != first {
+				return fmt.Errorf("telebot: album entry #%d can't be grouped with %s", i, first)
+			}
+		default:
+			return fmt.Errorf("telebot: album entry #%d has unsupported type %s", i, kind)
+		}
+	}
+	return nil
+}
+
 // Photo object represents a single photo file.
 type Photo struct {
 	File
@@ -1,8 +1,13 @@
 package telebot
 
 import (
-	"github.com/stretchr/testify/assert"
+	"bytes"
+	"io"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAlbumSetCaption(t *testing.T) {
@@ -42,3 +47,67 @@ func TestAlbumSetCaption(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectMediaType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+	mp4 := []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+
+	assert.Equal(t, MediaPhoto, DetectMediaType(bytes.NewReader(png)))
+	assert.Equal(t, MediaAnimation, DetectMediaType(bytes.NewReader(gif)))
+	assert.Equal(t, MediaVideo, DetectMediaType(bytes.NewReader(mp4)))
+	assert.Equal(t, MediaAudio, DetectMediaType(strings.NewReader("ID3\x03\x00\x00\x00")))
+	assert.Equal(t, MediaDocument, DetectMediaType(strings.NewReader("plain text")))
+	assert.Equal(t, MediaDocument, DetectMediaType(strings.NewReader("")))
+
+	f := FromReader(bytes.NewReader(png))
+	assert.Equal(t, MediaPhoto, f.detectMediaType())
+	data, err := io.ReadAll(f.FileReader)
+	require.NoError(t, err)
+	assert.Equal(t, png, data, "sniffed bytes must not be lost")
+
+	f = FromURL("https://example.com/clip.mp4?size=large")
+	assert.Equal(t, MediaVideo, f.detectMediaType())
+	f = File{FileID: "id"}
+	assert.Equal(t, MediaDocument, f.detectMediaType())
+}
+
+func TestBotSendFile(t *testing.T) {
+	var method string
+	srv := newTestAPI(t, func(call apiCall) string {
+		method = call.Method
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1},` +
+			`"photo":[{"file_id":"p"}],"video":{"file_id":"v"},"audio":{"file_id":"a"},` +
+			`"document":{"file_id":"d"},"animation":{"file_id":"g"}}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	to := &Chat{ID: 1}
+	mp4 := []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+
+	_, err = b.Send(to, FromReader(bytes.NewReader(mp4)))
+	require.NoError(t, err)
+	assert.Equal(t, "sendVideo", method)
+
+	_, err = b.Send(to, FromURL("https://example.com/track.mp3"))
+	require.NoError(t, err)
+	assert.Equal(t, "sendAudio", method)
+
+	f := FromURL("https://example.com/photo.jpg")
+	_, err = b.Send(to, &f)
+	require.NoError(t, err)
+	assert.Equal(t, "sendPhoto", method)
+
+	_, err = b.Send(to, FromURL("https://example.com/photo.jpg"), MediaDocument)
+	require.NoError(t, err)
+	assert.Equal(t, "sendDocument", method)
+
+	_, err = b.Send(to, File{FileID: "id"})
+	require.NoError(t, err)
+	assert.Equal(t, "sendDocument", method)
+
+	_, err = b.Send(to, (*File)(nil))
+	assert.Equal(t, ErrUnsupportedWhat, err)
+}
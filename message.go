@@ -1,7 +1,9 @@
 package telebot
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf16"
 )
@@ -89,6 +91,10 @@ type Message struct {
 	// for example, as an away or a greeting business message, or as a scheduled message
 	FromOffline bool `json:"is_from_offline,omitempty"`
 
+	// (Optional) Information about the direct messages chat topic
+	// that contains the message.
+	DirectMessagesTopic *DirectMessagesTopic `json:"direct_messages_topic,omitempty"`
+
 	// AlbumID is the unique identifier of a media message group
 	// this message belongs to.
 	AlbumID string `json:"media_group_id"`
@@ -319,6 +325,16 @@ type Message struct {
 	// Inline keyboard attached to the message.
 	ReplyMarkup *ReplyMarkup `json:"reply_markup,omitempty"`
 
+	// (Optional) Information about the suggested post, for messages
+	// in direct messages chats of channels only.
+	SuggestedPostInfo *SuggestedPostInfo `json:"suggested_post_info,omitempty"`
+
+	// Service message: a suggested post was approved.
+	SuggestedPostApproved *SuggestedPostApproved `json:"suggested_post_approved,omitempty"`
+
+	// Service message: a suggested post was declined.
+	SuggestedPostDeclined *SuggestedPostDeclined `json:"suggested_post_declined,omitempty"`
+
 	// Service message: user boosted the chat.
 	BoostAdded *BoostAdded `json:"boost_added"`
 
@@ -412,6 +428,20 @@ const (
 // Entities are used to set message's text entities as a send option.
 type Entities []MessageEntity
 
+// validateEntities checks that the entities fit into the text, counting
+// the offsets in UTF-16 code units. The field names the text in the error,
+// such as "message" or "poll question".
+func validateEntities(field, text string, entities []MessageEntity) error {
+	size := utf16Len(text)
+	for i, e := range entities {
+		if e.Offset < 0 || e.Length <= 0 || e.Offset+e.Length > size {
+			return fmt.Errorf("telebot: %s entity #%d (%s at %d, length %d) is out of the text of length %d",
+				field, i, e.Type, e.Offset, e.Length, size)
+		}
+	}
+	return nil
+}
+
 // ProximityAlert sent whenever a user in the chat triggers
 // a proximity alert set by another user.
 type ProximityAlert struct {
@@ -422,9 +452,16 @@ type ProximityAlert struct {
 
 // AutoDeleteTimer represents a service message about a change in auto-delete timer settings.
 type AutoDeleteTimer struct {
+	// Unixtime is the new auto-delete time in seconds, despite the name.
+	// Use AutoDeleteTimer.Duration() to get time.Duration.
 	Unixtime int `json:"message_auto_delete_time"`
 }
 
+// Duration returns the new auto-delete time, zero if it's disabled.
+func (t *AutoDeleteTimer) Duration() time.Duration {
+	return time.Duration(t.Unixtime) * time.Second
+}
+
 // Inaccessible shows whether the message is InaccessibleMessage object.
 func (m *Message) Inaccessible() bool {
 	return m.Sender == nil
@@ -456,6 +493,14 @@ func (m *Message) IsReply() bool {
 	return m.ReplyTo != nil
 }
 
+// Addressee returns the bot username the command is addressed to,
+// e.g. "mybot" for "/start@mybot", or an empty string if the command
+// isn't addressed or the message isn't a command.
+func (m *Message) Addressee() string {
+	_, bot, _, _ := SplitCommand(m.Text)
+	return bot
+}
+
 // Private returns true, if it's a personal message.
 func (m *Message) Private() bool {
 	return m.Chat.Type == ChatPrivate
@@ -471,6 +516,37 @@ func (m *Message) FromChannel() bool {
 	return m.Chat.Type == ChatChannel
 }
 
+// Link returns the permalink of the message, https://t.me/<username>/<id>
+// for the public chats and https://t.me/c/<chat>/<id> for the private
+// supergroups and channels. The messages of the forum topics are linked
+// within their topic. Returns an empty string if the chat is private or
+// a basic group, which have no links.
+func (m *Message) Link() string {
+	if m.Chat == nil || m.ID == 0 {
+		return ""
+	}
+
+	var link string
+	switch {
+	case m.Chat.Type == ChatPrivate || m.Chat.Type == ChatGroup:
+		return ""
+	case m.Chat.Username != "":
+		link = "https://t.me/" + m.Chat.Username
+	default:
+		// Supergroup and channel IDs are -100 followed by an internal ID
+		id := strconv.FormatInt(m.Chat.ID, 10)
+		if !strings.HasPrefix(id, "-100") || len(id) == len("-100") {
+			return ""
+		}
+		link = "https://t.me/c/" + id[len("-100"):]
+	}
+
+	if m.TopicMessage && m.ThreadID != 0 {
+		link += "/" + strconv.Itoa(m.ThreadID)
+	}
+	return link + "/" + strconv.Itoa(m.ID)
+}
+
 // IsService returns true, if message is a service message,
 // returns false otherwise.
 //
@@ -513,6 +589,81 @@ func (m *Message) EntityText(e MessageEntity) string {
 	return string(utf16.Decode(a[off:end]))
 }
 
+// Mentions returns the users mentioned in the message, in order. The
+// users mentioned by username only have the Username field set.
+func (m *Message) Mentions() []User {
+	var users []User
+	m.eachEntity(func(e MessageEntity, text string) {
+		switch e.Type {
+		case EntityMention:
+			users = append(users, User{Username: strings.TrimPrefix(text, "@")})
+		case EntityTMention:
+			if e.User != nil {
+				users = append(users, *e.User)
+			}
+		}
+	})
+	return users
+}
+
+// URLs returns the links in the message, both the plain ones
+// and the URLs of the text links, in order.
+func (m *Message) URLs() []string {
+	var urls []string
+	m.eachEntity(func(e MessageEntity, text string) {
+		switch e.Type {
+		case EntityURL:
+			urls = append(urls, text)
+		case EntityTextLink:
+			urls = append(urls, e.URL)
+		}
+	})
+	return urls
+}
+
+// Hashtags returns the hashtags in the message, including the # sign.
+func (m *Message) Hashtags() []string {
+	var tags []string
+	m.eachEntity(func(e MessageEntity, text string) {
+		if e.Type == EntityHashtag {
+			tags = append(tags, text)
+		}
+	})
+	return tags
+}
+
+// CustomEmoji returns the IDs of the custom emoji in the message.
+func (m *Message) CustomEmoji() []string {
+	var ids []string
+	m.eachEntity(func(e MessageEntity, _ string) {
+		if e.Type == EntityCustomEmoji {
+			ids = append(ids, e.CustomEmojiID)
+		}
+	})
+	return ids
+}
+
+// eachEntity calls f for each entity of the text, or the caption,
+// with the entity text, skipping the entities out of its bounds.
+func (m *Message) eachEntity(f func(e MessageEntity, text string)) {
+	text, entities := m.Text, m.Entities
+	if text == "" {
+		text, entities = m.Caption, m.CaptionEntities
+	}
+	if len(entities) == 0 {
+		return
+	}
+
+	a := utf16.Encode([]rune(text))
+	for _, e := range entities {
+		off, end := e.Offset, e.Offset+e.Length
+		if off < 0 || end > len(a) || off > end {
+			continue
+		}
+		f(e, string(utf16.Decode(a[off:end])))
+	}
+}
+
 // Media returns the message's media if it contains either photo,
 // voice, audio, animation, sticker, document, video or video note.
 func (m *Message) Media() Media {
@@ -538,6 +689,18 @@ func (m *Message) Media() Media {
 	}
 }
 
+// MediaFileUniqueID returns the unique identifier of the message's
+// media file, which is the same for all bots and for every forward
+// of the file. Use it to deduplicate files. Returns an empty string
+// if the message has no media.
+func (m *Message) MediaFileUniqueID() string {
+	media := m.Media()
+	if media == nil {
+		return ""
+	}
+	return media.MediaFile().UniqueID
+}
+
 // MessageReaction object represents a change of a reaction on a message performed by a user.
 type MessageReaction struct {
 	// The chat containing the message the user reacted to.
@@ -729,26 +892,72 @@ type ReplyParams struct {
 
 	// (Optional) If the message to be replied to is from a different chat,
 	// unique identifier for the chat or username of the channel.
-	ChatID int64 `json:"chat_id"`
+	ChatID int64 `json:"chat_id,omitempty"`
 
 	// Optional. Pass True if the message should be sent even if the specified message
 	// to be replied to is not found; can be used only for replies in the
 	// same chat and forum topic.
-	AllowWithoutReply bool `json:"allow_sending_without_reply"`
+	AllowWithoutReply bool `json:"allow_sending_without_reply,omitempty"`
 
 	// (Optional) Quoted part of the message to be replied to; 0-1024 characters after
 	// entities parsing. The quote must be an exact substring of the message to be replied to,
 	// including bold, italic, underline, strikethrough, spoiler, and custom_emoji entities.
 	// The message will fail to send if the quote isn't found in the original message.
-	Quote string `json:"quote"`
+	Quote string `json:"quote,omitempty"`
 
 	// (Optional) Mode for parsing entities in the quote.
-	QuoteParseMode ParseMode `json:"quote_parse_mode"`
+	QuoteParseMode ParseMode `json:"quote_parse_mode,omitempty"`
 
 	// (Optional) A JSON-serialized list of special entities that appear in the quote.
 	// It can be specified instead of quote_parse_mode.
-	QuoteEntities []MessageEntity `json:"quote_entities"`
+	QuoteEntities []MessageEntity `json:"quote_entities,omitempty"`
 
 	// (Optional) Position of the quote in the original message in UTF-16 code units.
-	QuotePosition int `json:"quote_position"`
+	QuotePosition int `json:"quote_position,omitempty"`
+}
+
+// QuoteReply returns the parameters of a reply to the message, which
+// quotes the fragment of its text or caption. The position and the
+// formatting entities of the quote are taken from the message, so
+// the first occurrence of the fragment is quoted as it's formatted.
+// It returns ErrQuoteNotFound if the message has no such fragment.
+//
+//	params, err := tele.QuoteReply(msg, "the fragment")
+//	b.Send(chat, "reply", params)
+func QuoteReply(m *Message, quote string) (*ReplyParams, error) {
+	text, entities := m.Text, m.Entities
+	if text == "" {
+		text, entities = m.Caption, m.CaptionEntities
+	}
+
+	i := strings.Index(text, quote)
+	if quote == "" || i < 0 {
+		return nil, ErrQuoteNotFound
+	}
+
+	params := &ReplyParams{
+		MessageID:     m.ID,
+		Quote:         quote,
+		QuotePosition: utf16Len(text[:i]),
+	}
+	if m.Chat != nil {
+		params.ChatID = m.Chat.ID
+	}
+
+	start, end := params.QuotePosition, params.QuotePosition+utf16Len(quote)
+	for _, e := range entities {
+		switch e.Type {
+		case EntityBold, EntityItalic, EntityUnderline, EntityStrikethrough, EntitySpoiler, EntityCustomEmoji:
+		default:
+			continue
+		}
+
+		from, to := max(e.Offset, start), min(e.Offset+e.Length, end)
+		if from >= to {
+			continue
+		}
+		e.Offset, e.Length = from-start, to-from
+		params.QuoteEntities = append(params.QuoteEntities, e)
+	}
+	return params, nil
 }
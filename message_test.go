@@ -0,0 +1,97 @@
+package telebot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageLink(t *testing.T) {
+	for _, tc := range []struct {
+		msg  *Message
+		link string
+	}{
+		{&Message{ID: 5, Chat: &Chat{ID: -1001234567890, Type: ChatSuperGroup}}, "https://t.me/c/1234567890/5"},
+		{&Message{ID: 5, Chat: &Chat{ID: -1001234567890, Type: ChatChannel, Username: "news"}}, "https://t.me/news/5"},
+		{&Message{ID: 5, ThreadID: 3, TopicMessage: true, Chat: &Chat{ID: -1001234567890, Type: ChatSuperGroup}}, "https://t.me/c/1234567890/3/5"},
+		{&Message{ID: 5, ThreadID: 3, TopicMessage: true, Chat: &Chat{ID: -1001234567890, Type: ChatSuperGroup, Username: "forum"}}, "https://t.me/forum/3/5"},
+		{&Message{ID: 5, ThreadID: 3, Chat: &Chat{ID: -1001234567890, Type: ChatSuperGroup}}, "https://t.me/c/1234567890/5"},
+		{&Message{ID: 5, Chat: &Chat{ID: 42, Type: ChatPrivate, Username: "user"}}, ""},
+		{&Message{ID: 5, Chat: &Chat{ID: -42, Type: ChatGroup}}, ""},
+		{&Message{ID: 5}, ""},
+	} {
+		assert.Equal(t, tc.link, tc.msg.Link())
+	}
+}
+
+func TestAutoDeleteTimer(t *testing.T) {
+	var m Message
+	require.NoError(t, json.Unmarshal([]byte(`{"message_auto_delete_timer_changed":{"message_auto_delete_time":86400}}`), &m))
+	require.NotNil(t, m.AutoDeleteTimer)
+	assert.Equal(t, 24*time.Hour, m.AutoDeleteTimer.Duration())
+
+	var chat Chat
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"message_auto_delete_time":604800}`), &chat))
+	assert.Equal(t, 604800, chat.AutoDeleteTime)
+}
+
+func TestQuoteReply(t *testing.T) {
+	msg := &Message{
+		ID:   5,
+		Chat: &Chat{ID: 42},
+		Text: "привет, hello world",
+		Entities: Entities{
+			{Type: EntityBold, Offset: 8, Length: 11},
+			{Type: EntityURL, Offset: 14, Length: 5},
+		},
+	}
+
+	params, err := QuoteReply(msg, "world")
+	require.NoError(t, err)
+	assert.Equal(t, &ReplyParams{
+		MessageID:     5,
+		ChatID:        42,
+		Quote:         "world",
+		QuotePosition: 14,
+		QuoteEntities: []MessageEntity{{Type: EntityBold, Offset: 0, Length: 5}},
+	}, params)
+
+	_, err = QuoteReply(msg, "bye")
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+
+	params, err = QuoteReply(&Message{ID: 1, Caption: "a caption"}, "caption")
+	require.NoError(t, err)
+	assert.Equal(t, 2, params.QuotePosition)
+	assert.Zero(t, params.ChatID)
+}
+
+func TestMessageEntities(t *testing.T) {
+	m := &Message{
+		Text: "👋 @bob see https://go.dev #go 🎉 docs",
+		Entities: Entities{
+			{Type: EntityMention, Offset: 3, Length: 4},
+			{Type: EntityURL, Offset: 12, Length: 14},
+			{Type: EntityHashtag, Offset: 27, Length: 3},
+			{Type: EntityCustomEmoji, Offset: 31, Length: 2, CustomEmojiID: "42"},
+			{Type: EntityTextLink, Offset: 34, Length: 4, URL: "https://pkg.go.dev"},
+			{Type: EntityTMention, Offset: 0, Length: 2, User: &User{ID: 7}},
+			{Type: EntityHashtag, Offset: 100, Length: 3},
+		},
+	}
+
+	assert.Equal(t, []User{{Username: "bob"}, {ID: 7}}, m.Mentions())
+	assert.Equal(t, []string{"https://go.dev", "https://pkg.go.dev"}, m.URLs())
+	assert.Equal(t, []string{"#go"}, m.Hashtags())
+	assert.Equal(t, []string{"42"}, m.CustomEmoji())
+
+	c := NewContext(nil, Update{Message: &Message{
+		Caption:         "#a #b",
+		CaptionEntities: Entities{{Type: EntityHashtag, Offset: 0, Length: 2}, {Type: EntityHashtag, Offset: 3, Length: 2}},
+	}})
+	assert.Equal(t, []string{"#a", "#b"}, c.Hashtags())
+	assert.Empty(t, c.URLs())
+	assert.Empty(t, NewContext(nil, Update{}).Mentions())
+}
@@ -1,5 +1,19 @@
 package telebot
 
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrSkip stops the handler chain on purpose. Return it from a handler
+// or a middleware, usually via Context.Abort, when the update is dealt
+// with and the rest of the chain must not run, e.g. the access is denied.
+//
+// Unlike a real error, ErrSkip is not passed to the OnError handler,
+// so it's neither reported nor logged. Middleware wrapping the chain
+// should return it as is.
+var ErrSkip = errors.New("telebot: handler chain aborted")
+
 // MiddlewareFunc represents a middleware processing function,
 // which get called before the endpoint group or specific handler.
 type MiddlewareFunc func(HandlerFunc) HandlerFunc
@@ -21,9 +35,40 @@ func applyMiddleware(h HandlerFunc, m ...MiddlewareFunc) HandlerFunc {
 }
 
 // Group is a separated group of handlers, united by the general middleware.
+//
+// Groups can be nested: a nested group runs the middleware of its parents
+// first, and its prefix follows theirs. The prefix is added to the commands,
+// callback uniques and callback patterns handled within the group, so the
+// "admin_" group handles "/ban" as "/admin_ban", &Btn{Unique: "ban"} as
+// "admin_ban" and the "ban:{id}" pattern as "admin_ban:{id}". Make the
+// buttons with the full unique or pattern. Other endpoints aren't prefixed.
+//
+//	admin := b.Group("admin_")
+//	admin.Use(middleware.Whitelist(adminIDs...))
+//	admin.Handle("/ban", onBan)
 type Group struct {
 	b          *Bot
+	parent     *Group
+	prefix     string
 	middleware []MiddlewareFunc
+	onError    func(error, Context)
+}
+
+// Group returns a new group nested in this one, see Bot.Group.
+func (g *Group) Group(prefix ...string) *Group {
+	ng := &Group{b: g.b, parent: g}
+	if len(prefix) > 0 {
+		ng.prefix = prefix[0]
+	}
+	return ng
+}
+
+// OnError sets the error handler of the group, which overrides
+// Settings.OnError for the updates handled within the group and
+// its nested groups, unless they have their own. The errors of the
+// global middleware preceding the group's one go to Settings.OnError.
+func (g *Group) OnError(onError func(error, Context)) {
+	g.onError = onError
 }
 
 // Use adds middleware to the chain.
@@ -34,5 +79,94 @@ func (g *Group) Use(middleware ...MiddlewareFunc) {
 // Handle adds endpoint handler to the bot, combining group's middleware
 // with the optional given middleware.
 func (g *Group) Handle(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
-	g.b.Handle(endpoint, h, appendMiddleware(g.middleware, m)...)
+	g.b.Handle(g.prefixed(endpoint), h, g.chain(m)...)
+}
+
+// HandleChats adds the endpoint handler restricted to the chat types,
+// combining group's middleware with the optional given middleware,
+// see Bot.HandleChats.
+func (g *Group) HandleChats(types []ChatType, endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	g.b.HandleChats(types, g.prefixed(endpoint), h, g.chain(m)...)
+}
+
+// HandlePrefix adds the prefix handler to the bot, combining group's
+// middleware with the optional given middleware, see Bot.HandlePrefix.
+func (g *Group) HandlePrefix(prefix string, h HandlerFunc, m ...MiddlewareFunc) {
+	g.b.HandlePrefix(prefix, h, g.chain(m)...)
+}
+
+// HandleRegexp adds the regexp handler to the bot, combining group's
+// middleware with the optional given middleware, see Bot.HandleRegexp.
+func (g *Group) HandleRegexp(pattern *regexp.Regexp, h HandlerFunc, m ...MiddlewareFunc) {
+	g.b.HandleRegexp(pattern, h, g.chain(m)...)
+}
+
+// HandleCallback adds the callback pattern handler to the bot, combining
+// group's middleware with the optional given middleware, see
+// Bot.HandleCallback. The pattern is prefixed like the callback uniques.
+func (g *Group) HandleCallback(pattern string, h HandlerFunc, m ...MiddlewareFunc) {
+	g.b.HandleCallback(g.fullPrefix()+pattern, h, g.chain(m)...)
+}
+
+// chain prepends the middleware of the group and its parents to m.
+func (g *Group) chain(m []MiddlewareFunc) []MiddlewareFunc {
+	for p := g; p != nil; p = p.parent {
+		m = appendMiddleware(p.middleware, m)
+	}
+	return appendMiddleware([]MiddlewareFunc{g.scope}, m)
+}
+
+// scope makes the errors of the update handled within
+// the group go to the group's error handler.
+func (g *Group) scope(next HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		if nc, ok := c.(*nativeContext); ok {
+			if onError := g.errorHandler(); onError != nil {
+				nc.setOnError(onError)
+			}
+		}
+		return next(c)
+	}
+}
+
+// errorHandler returns the error handler of the
+// group or its closest parent having one.
+func (g *Group) errorHandler() func(error, Context) {
+	for p := g; p != nil; p = p.parent {
+		if p.onError != nil {
+			return p.onError
+		}
+	}
+	return nil
+}
+
+// prefixed prefixes the endpoint, or each of its aliases.
+func (g *Group) prefixed(endpoint any) any {
+	if aliases, ok := endpoint.([]string); ok {
+		prefixed := make([]string, len(aliases))
+		for i, alias := range aliases {
+			prefixed[i] = g.endpoint(alias)
+		}
+		return prefixed
+	}
+	return g.endpoint(extractEndpoint(endpoint))
+}
+
+// endpoint prefixes the command or callback endpoint with the
+// prefixes of the group and its parents.
+func (g *Group) endpoint(end string) string {
+	prefix := g.fullPrefix()
+	if prefix == "" || end == "" || end[0] != '/' && end[0] != '\f' {
+		return end
+	}
+	return end[:1] + prefix + end[1:]
+}
+
+// fullPrefix returns the prefixes of the group and its parents.
+func (g *Group) fullPrefix() string {
+	var prefix string
+	for p := g; p != nil; p = p.parent {
+		prefix = p.prefix + prefix
+	}
+	return prefix
 }
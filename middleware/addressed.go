@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strings"
+
+	tele "github.com/nullcache/telebotx"
+)
+
+// Addressed returns a middleware that ignores the commands sent to the
+// group chats without the bot username, e.g. "/start" instead of
+// "/start@mybot", so the bots sharing a group don't answer each
+// other's commands. The commands addressed to other bots are always
+// ignored by the bot itself. Use it per handler:
+//
+//	b.Handle("/start", onStart, middleware.Addressed())
+//
+// The private chats and the updates other than commands pass as is.
+// The ignored commands abort the chain with tele.ErrSkip, so they
+// aren't reported as errors.
+//
+// The username is taken from Bot.Identity, so the bot must know it:
+// an offline bot, whose identity is an empty stub, ignores every
+// group command until Bot.RefreshIdentity is called.
+func Addressed() tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			msg := c.Message()
+			if msg == nil || msg.Chat == nil || !msg.FromGroup() || !strings.HasPrefix(msg.Text, "/") {
+				return next(c)
+			}
+
+			var me *tele.User
+			if b, ok := c.Bot().(*tele.Bot); ok {
+				me = b.Identity()
+			}
+			name := msg.Addressee()
+			if name == "" || me == nil || !strings.EqualFold(name, me.Username) {
+				return tele.ErrSkip
+			}
+			return next(c)
+		}
+	}
+}
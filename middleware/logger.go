@@ -3,6 +3,8 @@ package middleware
 import (
 	"encoding/json"
 	"log"
+	"strings"
+	"time"
 
 	tele "github.com/nullcache/telebotx"
 )
@@ -25,3 +27,55 @@ func Logger(logger ...*log.Logger) tele.MiddlewareFunc {
 		}
 	}
 }
+
+// SlowLog returns a middleware that logs a warning when the handler
+// takes longer than the threshold, with the update type, endpoint
+// and chat, so that the slow handler can be found. If no logger
+// provided, a default one of the Warn level will be used.
+func SlowLog(threshold time.Duration, logger tele.Logger) tele.MiddlewareFunc {
+	if logger == nil {
+		logger = tele.NewDefaultLogger(tele.LogLevelWarn, "")
+	}
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			if elapsed := time.Since(start); elapsed > threshold {
+				var chatID int64
+				if chat := c.Chat(); chat != nil {
+					chatID = chat.ID
+				}
+				logger.Warn("Slow handler for %s in chat %d took %s", describeUpdate(c), chatID, elapsed)
+			}
+			return err
+		}
+	}
+}
+
+// describeUpdate returns the update type along with the command
+// or the callback unique, if any.
+func describeUpdate(c tele.Context) string {
+	u := c.Update()
+
+	kind := string(u.Type())
+	switch {
+	case kind == "":
+		return "update"
+	case u.Callback != nil:
+		if u.Callback.Unique != "" {
+			return kind + " " + u.Callback.Unique
+		}
+		return kind
+	case c.Message() == nil:
+		return kind
+	}
+
+	// A command is the first word of the text, without the bot username
+	if text := c.Text(); strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(strings.Fields(text)[0], "@")
+		return kind + " " + command
+	}
+	return kind
+}
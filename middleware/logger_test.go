@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/nullcache/telebotx"
+)
+
+type warnLogger struct {
+	tele.NoOpLogger
+	warns []string
+}
+
+func (l *warnLogger) Warn(msg string, args ...any) {
+	l.warns = append(l.warns, fmt.Sprintf(msg, args...))
+}
+
+func TestSlowLog(t *testing.T) {
+	logger := &warnLogger{}
+	slow := SlowLog(5*time.Millisecond, logger)(func(c tele.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	fast := SlowLog(time.Second, logger)(func(c tele.Context) error {
+		return nil
+	})
+
+	msg := &tele.Message{Text: "/start@mybot now", Chat: &tele.Chat{ID: 42}}
+	require.NoError(t, fast(b.NewContext(tele.Update{Message: msg})))
+	assert.Empty(t, logger.warns)
+
+	require.NoError(t, slow(b.NewContext(tele.Update{Message: msg})))
+	require.NoError(t, slow(b.NewContext(tele.Update{Callback: &tele.Callback{Unique: "next", Message: msg}})))
+	require.NoError(t, slow(b.NewContext(tele.Update{Query: &tele.Query{}})))
+
+	require.Len(t, logger.warns, 3)
+	assert.Regexp(t, `^Slow handler for message /start in chat 42 took \d`, logger.warns[0])
+	assert.Regexp(t, `^Slow handler for callback_query next in chat 42 took`, logger.warns[1])
+	assert.Regexp(t, `^Slow handler for inline_query in chat 0 took`, logger.warns[2])
+}
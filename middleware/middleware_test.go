@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +30,45 @@ func TestRecover(t *testing.T) {
 		Recover(onError)(h)(nil)
 	})
 }
+
+func TestAddressed(t *testing.T) {
+	username := "mybot"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"username":"` + username + `"}}`))
+	}))
+	defer srv.Close()
+
+	b, err := tele.NewBot(tele.Settings{URL: srv.URL, Offline: true, Synchronous: true})
+	require.NoError(t, err)
+	_, err = b.RefreshIdentity()
+	require.NoError(t, err)
+
+	var fired []string
+	b.Handle("/start", func(c tele.Context) error {
+		fired = append(fired, c.Text())
+		return nil
+	}, Addressed())
+
+	group := &tele.Chat{Type: tele.ChatSuperGroup}
+	private := &tele.Chat{Type: tele.ChatPrivate}
+	for _, m := range []*tele.Message{
+		{Chat: group, Text: "/start"},
+		{Chat: group, Text: "/start@otherbot"},
+		{Chat: group, Text: "/start@MyBot"},
+		{Chat: private, Text: "/start"},
+	} {
+		b.ProcessUpdate(tele.Update{Message: m})
+	}
+	assert.Equal(t, []string{"/start@MyBot", "/start"}, fired)
+
+	h := Addressed()(func(tele.Context) error { return nil })
+	c := b.NewContext(tele.Update{Message: &tele.Message{Chat: group, Text: "/start"}})
+	assert.ErrorIs(t, h(c), tele.ErrSkip)
+
+	// The bot doesn't know its username
+	username = ""
+	_, err = b.RefreshIdentity()
+	require.NoError(t, err)
+	c = b.NewContext(tele.Update{Message: &tele.Message{Chat: group, Text: "/start@mybot"}})
+	assert.ErrorIs(t, h(c), tele.ErrSkip)
+}
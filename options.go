@@ -2,7 +2,9 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
+	"time"
 )
 
 // Option is a shortcut flag type for certain message features
@@ -37,6 +39,13 @@ const (
 
 	// IgnoreThread is used to ignore the thread when responding to a message via context.
 	IgnoreThread
+
+	// CaptionAbove = SendOptions.CaptionAbove
+	CaptionAbove
+
+	// DeleteCommand is used to delete the message the bot responds to
+	// along with the temporary message, see Context.SendTemp.
+	DeleteCommand
 )
 
 // Placeholder is used to set input field placeholder as a send option.
@@ -58,6 +67,7 @@ func Placeholder(text string) *SendOptions {
 // and re-using it somewhere or be using Option flags instead.
 type SendOptions struct {
 	// If the message is a reply, original message.
+	// Ignored if ReplyParams are set.
 	ReplyTo *Message
 
 	// See ReplyMarkup struct definition.
@@ -73,17 +83,31 @@ type SendOptions struct {
 	ParseMode ParseMode
 
 	// Entities is a list of special entities that appear in message text, which can be specified instead of parse_mode.
+	// They override the default parse mode of the bot, see ErrEntitiesParseMode.
 	Entities Entities
 
 	// AllowWithoutReply allows sending messages not a as reply if the replied-to message has already been deleted.
+	// It's the legacy flag, which is merged into ReplyParams if they are set.
 	AllowWithoutReply bool
 
 	// Protected protects the contents of sent message from forwarding and saving.
 	Protected bool
 
+	// CaptionAbove shows the caption above the media. It's only supported
+	// by photos, videos and animations, and ignored for other messages.
+	CaptionAbove bool
+
 	// ThreadID supports sending messages to a thread.
 	ThreadID int
 
+	// DirectMessagesTopicID supports sending messages to a topic
+	// of a channel direct messages chat.
+	DirectMessagesTopicID int64
+
+	// SuggestedPost describes the post suggested to a channel,
+	// for direct messages chats of channels only.
+	SuggestedPost *SuggestedPost
+
 	// HasSpoiler marks the message as containing a spoiler.
 	HasSpoiler bool
 
@@ -95,6 +119,17 @@ type SendOptions struct {
 
 	// Unique identifier of the message effect to be added to the message; for private chats only
 	EffectID string
+
+	// MediaType overrides the detected type of a plain File being sent.
+	MediaType MediaType
+
+	// RemoveCaption drops the captions of the messages copied
+	// with CopyMany or RelayMany.
+	RemoveCaption bool
+
+	// Caption replaces the caption of the message copied with Copy.
+	// Entities and ParseMode apply to it.
+	Caption string
 }
 
 func (og *SendOptions) copy() *SendOptions {
@@ -122,6 +157,12 @@ func (b *Bot) extractOptions(how []any) *SendOptions {
 			opts.ReplyParams = opt
 		case *Topic:
 			opts.ThreadID = opt.ThreadID
+		case *DirectMessagesTopic:
+			opts.DirectMessagesTopicID = opt.TopicID
+		case *SuggestedPost:
+			opts.SuggestedPost = opt
+		case *BusinessConnection:
+			opts.BusinessConnectionID = opt.ID
 		case Option:
 			switch opt {
 			case NoPreview:
@@ -147,6 +188,12 @@ func (b *Bot) extractOptions(how []any) *SendOptions {
 				opts.ReplyMarkup.RemoveKeyboard = true
 			case Protected:
 				opts.Protected = true
+			case IgnoreThread:
+				// Handled by the context, see Context.Send.
+			case DeleteCommand:
+				// Handled by the context, see Context.SendTemp.
+			case CaptionAbove:
+				opts.CaptionAbove = true
 			default:
 				panic("telebot: unsupported flag-option")
 			}
@@ -154,6 +201,8 @@ func (b *Bot) extractOptions(how []any) *SendOptions {
 			opts.ParseMode = opt
 		case Entities:
 			opts.Entities = opt
+		case MediaType:
+			opts.MediaType = opt
 		default:
 			panic("telebot: unsupported send-option")
 		}
@@ -162,15 +211,46 @@ func (b *Bot) extractOptions(how []any) *SendOptions {
 	return opts
 }
 
-func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
+func (og *SendOptions) validate(to Recipient, now time.Time) error {
+	// Sending without the reply is only possible within the same chat,
+	// so a missing message in another chat must fail the send.
+	if p := og.ReplyParams; p != nil && p.ChatID != 0 && (p.AllowWithoutReply || og.AllowWithoutReply) {
+		if id, err := strconv.ParseInt(to.Recipient(), 10, 64); err == nil && id != p.ChatID {
+			return errors.New("telebot: sending without reply is only allowed for replies in the same chat")
+		}
+	}
+
+	if og.ReplyMarkup != nil {
+		if err := og.ReplyMarkup.validate(); err != nil {
+			return err
+		}
+	}
+
+	if og.SuggestedPost != nil {
+		return og.SuggestedPost.validate(now)
+	}
+	return nil
+}
+
+func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) error {
 	if opt == nil {
-		return
+		return nil
 	}
 
-	if opt.ReplyTo != nil && opt.ReplyTo.ID != 0 {
+	// The reply parameters take precedence over the legacy fields
+	if opt.ReplyTo != nil && opt.ReplyTo.ID != 0 && opt.ReplyParams == nil {
 		params["reply_to_message_id"] = strconv.Itoa(opt.ReplyTo.ID)
 	}
 
+	if opt.ReplyParams != nil {
+		replyParams := *opt.ReplyParams
+		if opt.AllowWithoutReply {
+			replyParams.AllowWithoutReply = true
+		}
+		data, _ := json.Marshal(replyParams)
+		params["reply_parameters"] = string(data)
+	}
+
 	if opt.DisableWebPagePreview {
 		params["disable_web_page_preview"] = "true"
 	}
@@ -184,6 +264,11 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 	}
 
 	if len(opt.Entities) > 0 {
+		// The default parse mode of the bot gives way to the entities,
+		// while the one passed along with them is a mistake.
+		if opt.ParseMode != ModeDefault && opt.ParseMode != b.parseMode {
+			return ErrEntitiesParseMode
+		}
 		delete(params, "parse_mode")
 		entities, _ := json.Marshal(opt.Entities)
 
@@ -194,12 +279,14 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 		}
 	}
 
-	if opt.AllowWithoutReply {
+	if opt.AllowWithoutReply && opt.ReplyParams == nil {
 		params["allow_sending_without_reply"] = "true"
 	}
 
 	if opt.ReplyMarkup != nil {
-		processButtons(opt.ReplyMarkup.InlineKeyboard)
+		if err := b.processButtons(opt.ReplyMarkup.InlineKeyboard); err != nil {
+			return err
+		}
 		replyMarkup, _ := json.Marshal(opt.ReplyMarkup)
 		params["reply_markup"] = string(replyMarkup)
 	}
@@ -208,10 +295,23 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 		params["protect_content"] = "true"
 	}
 
+	if opt.CaptionAbove {
+		params["show_caption_above_media"] = "true"
+	}
+
 	if opt.ThreadID != 0 {
 		params["message_thread_id"] = strconv.Itoa(opt.ThreadID)
 	}
 
+	if opt.DirectMessagesTopicID != 0 {
+		params["direct_messages_topic_id"] = strconv.FormatInt(opt.DirectMessagesTopicID, 10)
+	}
+
+	if opt.SuggestedPost != nil {
+		suggestedPost, _ := json.Marshal(opt.SuggestedPost)
+		params["suggested_post_parameters"] = string(suggestedPost)
+	}
+
 	if opt.HasSpoiler {
 		params["has_spoiler"] = "true"
 	}
@@ -223,11 +323,22 @@ func (b *Bot) embedSendOptions(params map[string]string, opt *SendOptions) {
 	if opt.EffectID != "" {
 		params["message_effect_id"] = opt.EffectID
 	}
+
+	if opt.RemoveCaption {
+		params["remove_caption"] = "true"
+	}
+	return nil
 }
 
-func processButtons(keys [][]InlineButton) {
+// processButtons stores the button states and formats the callback data.
+// The buttons whose state can't be stored would never resolve, so the
+// error is returned and the message mustn't be sent.
+func (b *Bot) processButtons(keys [][]InlineButton) error {
 	if len(keys) < 1 || len(keys[0]) < 1 {
-		return
+		return nil
+	}
+	if err := b.storeButtonStates(keys); err != nil {
+		return err
 	}
 
 	for i := range keys {
@@ -244,6 +355,28 @@ func processButtons(keys [][]InlineButton) {
 			}
 		}
 	}
+	return nil
+}
+
+// storeButtonStates puts the button states to the store,
+// see Btn.WithState.
+func (b *Bot) storeButtonStates(keys [][]InlineButton) error {
+	for i := range keys {
+		for j := range keys[i] {
+			key := &keys[i][j]
+			if key.state == nil {
+				continue
+			}
+			// Format: "<data>\v<token>"
+			token, err := b.storeCallbackState(key.state)
+			if err != nil {
+				return err
+			}
+			key.Data += "\v" + token
+			key.state = nil
+		}
+	}
+	return nil
 }
 
 // PreviewOptions describes the options used for link preview generation.
@@ -184,6 +184,8 @@ func (c Currency) ToTotal(total float64) int {
 
 // CreateInvoiceLink creates a link for a payment invoice.
 func (b *Bot) CreateInvoiceLink(i Invoice) (string, error) {
+	b.redactor.add(i.Token)
+
 	data, err := b.Raw("createInvoiceLink", i.params())
 	if err != nil {
 		return "", err
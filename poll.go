@@ -1,6 +1,9 @@
 package telebot
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // PollType defines poll types.
 type PollType string
@@ -49,6 +52,14 @@ type PollOption struct {
 	Entities   []MessageEntity `json:"text_entities,omitempty"`
 }
 
+// inputPollOption represents an InputPollOption object, which
+// poll options are sent with.
+type inputPollOption struct {
+	Text      string          `json:"text"`
+	ParseMode ParseMode       `json:"text_parse_mode,omitempty"`
+	Entities  []MessageEntity `json:"text_entities,omitempty"`
+}
+
 // PollAnswer represents an answer of a user in a non-anonymous poll.
 type PollAnswer struct {
 	PollID  string `json:"poll_id"`
@@ -78,3 +89,32 @@ func (p *Poll) AddOptions(opts ...string) {
 		p.Options = append(p.Options, PollOption{Text: t})
 	}
 }
+
+// validate checks the formatting of the poll question, explanation
+// and options: parse mode and entities are mutually exclusive, and
+// entities must fit the text in UTF-16 code units.
+func (p *Poll) validate() error {
+	if err := validateFormatting("question", p.Question, p.QuestionParseMode, p.QuestionEntities); err != nil {
+		return err
+	}
+	if err := validateFormatting("explanation", p.Explanation, p.ParseMode, p.Entities); err != nil {
+		return err
+	}
+	for i, o := range p.Options {
+		if err := validateFormatting(fmt.Sprintf("option #%d", i), o.Text, o.ParseMode, o.Entities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFormatting(field, text string, mode ParseMode, entities []MessageEntity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	if mode != ModeDefault {
+		return fmt.Errorf("telebot: poll %s can't have both parse mode and entities", field)
+	}
+
+	return validateEntities("poll "+field, text, entities)
+}
@@ -18,6 +18,27 @@ func TestPoll(t *testing.T) {
 	assert.Equal(t, opts, p.Options)
 }
 
+func TestPollValidate(t *testing.T) {
+	bold := []MessageEntity{{Type: EntityBold, Offset: 0, Length: 6}}
+
+	p := &Poll{Question: "Answer", QuestionEntities: bold}
+	assert.NoError(t, p.validate())
+
+	p.QuestionParseMode = ModeHTML
+	assert.Error(t, p.validate())
+
+	// "🙂 Answer" is 9 UTF-16 code units long, while it has 8 runes
+	p = &Poll{Type: PollQuiz, Explanation: "🙂 Answer"}
+	p.Entities = []MessageEntity{{Type: EntityBold, Offset: 3, Length: 6}}
+	assert.NoError(t, p.validate())
+
+	p.Entities = []MessageEntity{{Type: EntityBold, Offset: 3, Length: 7}}
+	assert.Error(t, p.validate())
+
+	p = &Poll{Options: []PollOption{{Text: "1", ParseMode: ModeHTML, Entities: bold}}}
+	assert.Error(t, p.validate())
+}
+
 func TestPollSend(t *testing.T) {
 	if b == nil {
 		t.Skip("Cached bot instance is bad (probably wrong or empty TELEBOT_SECRET)")
@@ -1,6 +1,9 @@
 package telebot
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 var AllowedUpdates = []string{
 	"message",
@@ -74,7 +77,22 @@ func (p *LongPoller) Poll(b *Bot, dest chan Update, stop chan struct{}) {
 
 		updates, err := b.getUpdates(p.LastUpdateID+1, p.Limit, p.Timeout, p.AllowedUpdates)
 		if err != nil {
-			b.debug(err)
+			var floodErr FloodError
+			if errors.As(err, &floodErr) {
+				// LastUpdateID is left as is, so the next poll
+				// fetches the same updates from the same offset.
+				retryAfter := time.Duration(floodErr.RetryAfter) * time.Second
+				b.pollLogger.Warn("getUpdates is rate limited, retrying after %v", retryAfter)
+
+				select {
+				case <-stop:
+					return
+				case <-b.clock.After(retryAfter):
+				}
+				continue
+			}
+
+			b.pollLogger.Warn("getUpdates failed: %v", err)
 			continue
 		}
 
@@ -1,9 +1,16 @@
 package telebot
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testPoller struct {
@@ -64,3 +71,84 @@ func TestMiddlewarePoller(t *testing.T) {
 	assert.Contains(t, ids, 1)
 	assert.Contains(t, ids, 2)
 }
+
+func TestLongPollerFlood(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		offsets []string
+	)
+	srv := newTestAPI(t, func(call apiCall) string {
+		mu.Lock()
+		offsets = append(offsets, call.Params["offset"])
+		n := len(offsets)
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			return `{"ok":true,"result":[{"update_id":1}]}`
+		case 2:
+			return `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		default:
+			return `{"ok":true,"result":[{"update_id":2}]}`
+		}
+	})
+
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Log: &LogConfig{Enable: true, Logger: logger}})
+	require.NoError(t, err)
+
+	p := &LongPoller{}
+	dest := make(chan Update, 2)
+	stop := make(chan struct{})
+	go p.Poll(b, dest, stop)
+
+	start := time.Now()
+	assert.Equal(t, 1, (<-dest).ID)
+	assert.Equal(t, 2, (<-dest).ID)
+	close(stop)
+
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Contains(t, logger.GetOutput(), "[WARN] [poller] getUpdates is rate limited")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"1", "2", "2"}, offsets[:3])
+}
+
+func TestLongPollerErrorSampling(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 20 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"ok":false,"error_code":502,"description":"Bad Gateway"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":[{"update_id":1}]}`))
+	}))
+	defer srv.Close()
+
+	var reported atomic.Int32
+	logger := NewCustomTestLogger()
+	b, err := NewBot(Settings{
+		URL:     srv.URL,
+		Offline: true,
+		Verbose: true,
+		OnError: func(error, Context) { reported.Add(1) },
+		Log:     &LogConfig{Enable: true, Logger: logger},
+	})
+	require.NoError(t, err)
+
+	p := &LongPoller{}
+	dest := make(chan Update, 1)
+	stop := make(chan struct{})
+	go p.Poll(b, dest, stop)
+
+	assert.Equal(t, 1, (<-dest).ID)
+	close(stop)
+
+	failed := strings.Count(logger.GetOutput(), "getUpdates failed")
+	assert.GreaterOrEqual(t, failed, 1)
+	assert.Less(t, failed, 20)
+	assert.Equal(t, uint64(20-failed), b.pollLogger.Suppressed())
+	assert.Zero(t, reported.Load(), "the failures aren't reported on top of the sampled log")
+}
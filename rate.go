@@ -0,0 +1,367 @@
+package telebot
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SendRateConfig configures the send governor, which spaces out
+// the messages sent to the same chat, so that the chat doesn't
+// receive more than Rate messages per second.
+//
+// By default, the rate is fixed. In the adaptive mode, the governor
+// follows AIMD (additive increase, multiplicative decrease) per chat:
+// a flood error multiplies the chat rate by Decrease, and every
+// successful send adds Increase to it, up to the Rate limit.
+type SendRateConfig struct {
+	// Rate is the maximum number of messages per second
+	// sent to a single chat, defaulted to 1.
+	Rate float64
+
+	// Adaptive enables the adaptive mode.
+	Adaptive bool
+
+	// MinRate is the lowest rate the adaptive mode can set,
+	// defaulted to a tenth of Rate.
+	MinRate float64
+
+	// Increase is added to the chat rate after a successful send
+	// in the adaptive mode, defaulted to a tenth of Rate.
+	Increase float64
+
+	// Decrease multiplies the chat rate after a flood error
+	// in the adaptive mode, defaulted to 0.5.
+	Decrease float64
+
+	// MaxChats limits the number of chats the governor keeps track of,
+	// the least recently used ones are forgotten. Defaulted to 10000.
+	MaxChats int
+}
+
+type sendGovernor struct {
+	SendRateConfig
+
+	logger Logger
+	clock  Clock
+	mu     sync.Mutex
+	chats  map[string]*chatRate
+	recent *list.List // of *chatRate, the most recently used first
+}
+
+type chatRate struct {
+	chat string
+	rate float64
+	next time.Time
+	elem *list.Element
+}
+
+func newSendGovernor(cfg SendRateConfig, logger Logger, clock Clock) *sendGovernor {
+	if cfg.Rate <= 0 {
+		cfg.Rate = 1
+	}
+	if cfg.MinRate <= 0 || cfg.MinRate > cfg.Rate {
+		cfg.MinRate = cfg.Rate / 10
+	}
+	if cfg.Increase <= 0 {
+		cfg.Increase = cfg.Rate / 10
+	}
+	if cfg.Decrease <= 0 || cfg.Decrease >= 1 {
+		cfg.Decrease = 0.5
+	}
+	if cfg.MaxChats <= 0 {
+		cfg.MaxChats = 10000
+	}
+
+	return &sendGovernor{
+		SendRateConfig: cfg,
+		logger:         logger,
+		clock:          clock,
+		chats:          make(map[string]*chatRate),
+		recent:         list.New(),
+	}
+}
+
+// state returns the chat state and marks it as the most recently used,
+// forgetting the least recently used chat if there are too many.
+// g.mu must be held.
+func (g *sendGovernor) state(chat string) *chatRate {
+	s, ok := g.chats[chat]
+	if ok {
+		g.recent.MoveToFront(s.elem)
+		return s
+	}
+
+	if len(g.chats) >= g.MaxChats {
+		oldest := g.recent.Remove(g.recent.Back()).(*chatRate)
+		delete(g.chats, oldest.chat)
+	}
+
+	s = &chatRate{chat: chat, rate: g.Rate}
+	s.elem = g.recent.PushFront(s)
+	g.chats[chat] = s
+	return s
+}
+
+// rate returns the current rate of the chat.
+func (g *sendGovernor) rate(chat string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state(chat).rate
+}
+
+// wait blocks until a message can be sent to the chat. If the context
+// is done first, the reserved slot is released, unless another message
+// has been scheduled after it since.
+func (g *sendGovernor) wait(ctx context.Context, chat string) error {
+	g.mu.Lock()
+	s := g.state(chat)
+	now := g.clock.Now()
+	prev := s.next
+	at := prev
+	if at.Before(now) {
+		at = now
+	}
+	next := at.Add(time.Duration(float64(time.Second) / s.rate))
+	s.next = next
+	g.mu.Unlock()
+
+	d := at.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := g.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		if s.next.Equal(next) {
+			s.next = prev
+		}
+		g.mu.Unlock()
+		return wrapError(ctx.Err())
+	}
+}
+
+// report adjusts the chat rate according to the send result.
+func (g *sendGovernor) report(chat string, err error) {
+	var floodErr FloodError
+	flood := errors.As(err, &floodErr)
+	if err != nil && !flood {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.state(chat)
+	if flood {
+		if next := g.clock.Now().Add(time.Duration(floodErr.RetryAfter) * time.Second); next.After(s.next) {
+			s.next = next
+		}
+	}
+	if !g.Adaptive {
+		return
+	}
+
+	old := s.rate
+	if flood {
+		s.rate = max(s.rate*g.Decrease, g.MinRate)
+	} else {
+		s.rate = min(s.rate+g.Increase, g.Rate)
+	}
+
+	if s.rate != old {
+		g.logger.Debug("Send rate for chat %s changed from %.2f to %.2f per second", chat, old, s.rate)
+	}
+}
+
+// Telegram limits, roughly: 30 messages per second overall, about one
+// message per second in a private chat and 20 messages per minute in
+// a group or channel.
+const (
+	globalSendLimit = 30
+	globalSendSpan  = time.Second
+	chatSendLimit   = 60
+	groupSendLimit  = 20
+	chatSendSpan    = time.Minute
+
+	maxMeteredChats = 10000
+)
+
+// RateWarning is reported when the send rate approaches the Telegram
+// limits, see Settings.RateWarningThreshold.
+type RateWarning struct {
+	// Chat is the chat the messages are sent to,
+	// empty for the overall rate of the bot.
+	Chat string
+
+	// Sent is the estimated number of messages sent within the window.
+	Sent int
+
+	// Limit is the number of messages Telegram allows within the window.
+	Limit int
+
+	// Window is the span of the limit.
+	Window time.Duration
+}
+
+// sendMeter tracks the send rate with sliding window counters, and
+// warns once per window when the rate exceeds the threshold fraction
+// of the limit. It only observes the sends, see sendGovernor for the
+// enforcement.
+type sendMeter struct {
+	threshold float64
+	onWarning func(RateWarning)
+
+	logger   Logger
+	clock    Clock
+	mu       sync.Mutex
+	global   rateWindow
+	chats    map[string]*rateWindow
+	recent   *list.List // of *rateWindow, the most recently sent to first
+	maxChats int
+}
+
+// rateWindow approximates a sliding window with a weighted count
+// of the previous fixed window.
+type rateWindow struct {
+	start     time.Time
+	prev, cur int
+	warned    bool
+
+	chat string
+	elem *list.Element
+}
+
+func newSendMeter(threshold float64, onWarning func(RateWarning), logger Logger, clock Clock) *sendMeter {
+	return &sendMeter{
+		threshold: threshold,
+		onWarning: onWarning,
+		logger:    logger,
+		clock:     clock,
+		chats:     make(map[string]*rateWindow),
+		recent:    list.New(),
+		maxChats:  maxMeteredChats,
+	}
+}
+
+// add counts a message and returns the estimated number of messages
+// within the last span, and whether the warning is still due.
+func (w *rateWindow) add(now time.Time, span time.Duration) (int, bool) {
+	switch elapsed := now.Sub(w.start); {
+	case elapsed >= 2*span:
+		w.start, w.prev, w.cur, w.warned = now, 0, 0, false
+	case elapsed >= span:
+		w.start, w.prev, w.cur, w.warned = w.start.Add(span), w.cur, 0, false
+	}
+
+	w.cur++
+	weight := 1 - float64(now.Sub(w.start))/float64(span)
+	return w.cur + int(float64(w.prev)*weight), !w.warned
+}
+
+// observe counts a message sent to the chat.
+func (m *sendMeter) observe(chat string) {
+	limit := chatSendLimit
+	if strings.HasPrefix(chat, "-") || strings.HasPrefix(chat, "@") {
+		limit = groupSendLimit
+	}
+
+	m.mu.Lock()
+	now := m.clock.Now()
+	warnings := make([]RateWarning, 0, 2)
+
+	if sent, due := m.global.add(now, globalSendSpan); due && m.exceeds(sent, globalSendLimit) {
+		m.global.warned = true
+		warnings = append(warnings, RateWarning{Sent: sent, Limit: globalSendLimit, Window: globalSendSpan})
+	}
+
+	w := m.window(chat)
+	if sent, due := w.add(now, chatSendSpan); due && m.exceeds(sent, limit) {
+		w.warned = true
+		warnings = append(warnings, RateWarning{Chat: chat, Sent: sent, Limit: limit, Window: chatSendSpan})
+	}
+	m.mu.Unlock()
+
+	for _, rw := range warnings {
+		target := "all chats"
+		if rw.Chat != "" {
+			target = "chat " + rw.Chat
+		}
+		m.logger.Warn("Send rate for %s is %d of %d messages per %s", target, rw.Sent, rw.Limit, rw.Window)
+		if m.onWarning != nil {
+			m.onWarning(rw)
+		}
+	}
+}
+
+func (m *sendMeter) exceeds(sent, limit int) bool {
+	return float64(sent) >= m.threshold*float64(limit)
+}
+
+// window returns the chat window and marks it as the most recently
+// used, forgetting the least recently used chat if there are too many.
+// m.mu must be held.
+func (m *sendMeter) window(chat string) *rateWindow {
+	if w, ok := m.chats[chat]; ok {
+		m.recent.MoveToFront(w.elem)
+		return w
+	}
+
+	if len(m.chats) >= m.maxChats {
+		oldest := m.recent.Remove(m.recent.Back()).(*rateWindow)
+		delete(m.chats, oldest.chat)
+	}
+
+	w := &rateWindow{chat: chat}
+	w.elem = m.recent.PushFront(w)
+	m.chats[chat] = w
+	return w
+}
+
+// throttle waits for the send governor if the method sends messages
+// to a chat. The returned function must be called with the result.
+func (b *Bot) throttle(method string, payload any) (func(error), error) {
+	noop := func(error) {}
+	if (b.sendRate == nil && b.sendMeter == nil) || !isSendMethod(method) {
+		return noop, nil
+	}
+
+	params, ok := payload.(map[string]string)
+	if !ok || params["chat_id"] == "" {
+		return noop, nil
+	}
+
+	chat := params["chat_id"]
+	if b.sendRate != nil {
+		if err := b.sendRate.wait(b.requestCtx(), chat); err != nil {
+			return nil, err
+		}
+	}
+	if b.sendMeter != nil {
+		b.sendMeter.observe(chat)
+	}
+	if b.sendRate == nil {
+		return noop, nil
+	}
+	return func(err error) { b.sendRate.report(chat, err) }, nil
+}
+
+func isSendMethod(method string) bool {
+	switch method {
+	case "sendChatAction":
+		return false
+	case "copyMessage", "copyMessages", "forwardMessage", "forwardMessages":
+		return true
+	default:
+		return strings.HasPrefix(method, "send")
+	}
+}
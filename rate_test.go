@@ -0,0 +1,163 @@
+package telebot
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendGovernor(t *testing.T) {
+	flood := FloodError{err: NewError(429, "Too Many Requests")}
+
+	t.Run("fixed", func(t *testing.T) {
+		g := newSendGovernor(SendRateConfig{Rate: 50}, NewNoOpLogger(), realClock{})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, g.wait(context.Background(), "1"))
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+		// Other chats aren't affected
+		start = time.Now()
+		require.NoError(t, g.wait(context.Background(), "2"))
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+		g.report("1", flood)
+		assert.Equal(t, 50.0, g.rate("1"))
+	})
+
+	t.Run("adaptive", func(t *testing.T) {
+		logger := NewCustomTestLogger()
+		g := newSendGovernor(SendRateConfig{Rate: 10, Adaptive: true, MinRate: 2}, logger, realClock{})
+
+		g.report("1", flood)
+		assert.Equal(t, 5.0, g.rate("1"))
+		g.report("1", flood)
+		g.report("1", flood)
+		assert.Equal(t, 2.0, g.rate("1"))
+
+		g.report("1", nil)
+		assert.Equal(t, 3.0, g.rate("1"))
+		g.report("1", ErrNotFound)
+		assert.Equal(t, 3.0, g.rate("1"))
+
+		for i := 0; i < 10; i++ {
+			g.report("1", nil)
+		}
+		assert.Equal(t, 10.0, g.rate("1"))
+		assert.Contains(t, logger.GetOutput(), "Send rate for chat 1 changed from 10.00 to 5.00")
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		g := newSendGovernor(SendRateConfig{Rate: 1000, MaxChats: 2}, NewNoOpLogger(), realClock{})
+
+		for _, chat := range []string{"1", "2", "1", "3"} {
+			require.NoError(t, g.wait(context.Background(), chat))
+		}
+		assert.Len(t, g.chats, 2)
+		assert.Equal(t, 2, g.recent.Len())
+		assert.NotContains(t, g.chats, "2", "the least recently used chat is forgotten")
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		g := newSendGovernor(SendRateConfig{Rate: 0.1}, NewNoOpLogger(), realClock{})
+		require.NoError(t, g.wait(context.Background(), "1"))
+
+		next := g.chats["1"].next
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Error(t, g.wait(ctx, "1"))
+		assert.Equal(t, next, g.chats["1"].next, "the slot is released")
+	})
+}
+
+func TestBotSendRate(t *testing.T) {
+	srv := newTestAPI(t, func(apiCall) string {
+		return `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 0","parameters":{"retry_after":0}}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, SendRate: &SendRateConfig{Rate: 100, Adaptive: true}})
+	require.NoError(t, err)
+
+	_, err = b.Send(&Chat{ID: 1}, "text")
+	assert.IsType(t, FloodError{}, err)
+	assert.Equal(t, 50.0, b.sendRate.rate("1"))
+
+	// Chat actions aren't governed
+	assert.Error(t, b.Notify(&Chat{ID: 1}, Typing))
+	assert.Equal(t, 50.0, b.sendRate.rate("1"))
+}
+
+func TestSendMeter(t *testing.T) {
+	var (
+		clock    = &stubClock{now: time.Now()}
+		logger   = NewCustomTestLogger()
+		warnings []RateWarning
+	)
+	m := newSendMeter(0.5, func(w RateWarning) { warnings = append(warnings, w) }, logger, clock)
+
+	for i := 0; i < 12; i++ {
+		m.observe("-100")
+	}
+	require.Len(t, warnings, 1, "warned once per window")
+	assert.Equal(t, RateWarning{Chat: "-100", Sent: 10, Limit: groupSendLimit, Window: time.Minute}, warnings[0])
+	assert.Contains(t, logger.GetOutput(), "Send rate for chat -100 is 10 of 20 messages per 1m0s")
+
+	// Private chats have a higher limit, but the overall one is reached
+	for i := 0; i < 6; i++ {
+		m.observe(strconv.Itoa(i))
+	}
+	require.Len(t, warnings, 2)
+	assert.Equal(t, RateWarning{Sent: 15, Limit: globalSendLimit, Window: time.Second}, warnings[1])
+
+	// The previous window still counts, partially
+	clock.now = clock.now.Add(90 * time.Second)
+	m.observe("-100")
+	assert.Len(t, warnings, 2)
+
+	clock.now = clock.now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		m.observe("-100")
+	}
+	assert.Len(t, warnings, 3)
+
+	// Only the least recently used chat is forgotten at capacity,
+	// the busy one keeps its count
+	m = newSendMeter(0.5, nil, NewNoOpLogger(), clock)
+	m.maxChats = 2
+	m.observe("-100")
+	m.observe("1")
+	m.observe("-100")
+	m.observe("2")
+	assert.Len(t, m.chats, 2)
+	assert.NotContains(t, m.chats, "1")
+	assert.Equal(t, 2, m.chats["-100"].cur)
+}
+
+func TestBotRateWarning(t *testing.T) {
+	srv := newTestAPI(t, func(apiCall) string {
+		return `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`
+	})
+
+	var warning RateWarning
+	b, err := NewBot(Settings{
+		URL:                  srv.URL,
+		Offline:              true,
+		RateWarningThreshold: 0.1,
+		OnRateWarning:        func(w RateWarning) { warning = w },
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = b.Send(&Chat{ID: -1}, "text")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "-1", warning.Chat)
+	assert.Equal(t, 2, warning.Sent)
+}
@@ -2,11 +2,15 @@ package telebot
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 )
 
 const (
 	ReactionTypeEmoji       = "emoji"
 	ReactionTypeCustomEmoji = "custom_emoji"
+	ReactionTypePaid        = "paid"
 )
 
 // Reaction describes the type of reaction.
@@ -22,6 +26,23 @@ type Reaction struct {
 	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
 }
 
+func (r Reaction) validate() error {
+	switch r.Type {
+	case ReactionTypeEmoji:
+		if r.Emoji == "" {
+			return errors.New("telebot: emoji reaction has no emoji")
+		}
+	case ReactionTypeCustomEmoji:
+		if _, err := strconv.ParseUint(r.CustomEmojiID, 10, 64); err != nil {
+			return fmt.Errorf("telebot: invalid custom emoji id %q", r.CustomEmojiID)
+		}
+	case ReactionTypePaid:
+	default:
+		return fmt.Errorf("telebot: unknown reaction type %q", r.Type)
+	}
+	return nil
+}
+
 // ReactionCount represents a reaction added to a message along
 // with the number of times it was added.
 type ReactionCount struct {
@@ -49,6 +70,12 @@ func (b *Bot) React(to Recipient, msg Editable, r Reactions) error {
 		return ErrBadRecipient
 	}
 
+	for _, reaction := range r.Reactions {
+		if err := reaction.validate(); err != nil {
+			return err
+		}
+	}
+
 	msgID, _ := msg.MessageSig()
 	params := map[string]string{
 		"chat_id":    to.Recipient(),
@@ -0,0 +1,137 @@
+package telebot
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// redactedText replaces the secrets in the log output.
+const redactedText = "<redacted>"
+
+// redactor keeps the secrets the framework must not log: the bot
+// token, the webhook secret token and the payment provider tokens,
+// see LogConfig.DisableRedaction.
+type redactor struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// add remembers the secrets, the empty and the known ones are skipped.
+func (r *redactor) add(secrets ...string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, secret := range secrets {
+		if secret != "" && !contains(r.secrets, secret) {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces the secrets in s.
+func (r *redactor) redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, secret := range r.secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, redactedText)
+		}
+	}
+	return s
+}
+
+// redactArgs replaces the secrets in the string and the error args,
+// e.g. the HTTP errors, which embed the request URL with the token.
+// The args without secrets are kept as is.
+func (r *redactor) redactArgs(args []any) []any {
+	var redacted []any
+	for i, arg := range args {
+		var s, safe string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		default:
+			continue
+		}
+
+		if safe = r.redact(s); safe == s {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]any(nil), args...)
+		}
+		if _, ok := arg.(error); ok {
+			redacted[i] = errors.New(safe)
+		} else {
+			redacted[i] = safe
+		}
+	}
+
+	if redacted == nil {
+		return args
+	}
+	return redacted
+}
+
+// redactError returns the error with the secrets redacted,
+// or the error itself if it has none.
+func (r *redactor) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if safe := r.redact(err.Error()); safe != err.Error() {
+		return errors.New(safe)
+	}
+	return err
+}
+
+// redactingLogger removes the secrets from everything it writes.
+type redactingLogger struct {
+	Logger
+	r *redactor
+}
+
+func (l *redactingLogger) Debug(msg string, args ...any) {
+	l.Logger.Debug(l.r.redact(msg), l.r.redactArgs(args)...)
+}
+
+func (l *redactingLogger) Info(msg string, args ...any) {
+	l.Logger.Info(l.r.redact(msg), l.r.redactArgs(args)...)
+}
+
+func (l *redactingLogger) Warn(msg string, args ...any) {
+	l.Logger.Warn(l.r.redact(msg), l.r.redactArgs(args)...)
+}
+
+func (l *redactingLogger) Error(msg string, args ...any) {
+	l.Logger.Error(l.r.redact(msg), l.r.redactArgs(args)...)
+}
+
+func (l *redactingLogger) Fatal(msg string, args ...any) {
+	l.Logger.Fatal(l.r.redact(msg), l.r.redactArgs(args)...)
+}
+
+func (l *redactingLogger) With(args ...any) Logger {
+	return &redactingLogger{Logger: LoggerWith(l.Logger, l.r.redactArgs(args)...), r: l.r}
+}
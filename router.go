@@ -0,0 +1,112 @@
+package telebot
+
+import "regexp"
+
+// Router is a set of handlers built apart from any bot, so a feature,
+// such as an admin panel or a feedback form, can be shipped as a package
+// and mounted into the bots using it:
+//
+//	func Feedback() *tele.Router {
+//		r := tele.NewRouter()
+//		r.Use(middleware.AutoRespond())
+//		r.Handle("/feedback", onFeedback)
+//		r.HandleCallback("rate:{stars}", onRate)
+//		return r
+//	}
+//
+//	b.Mount(feedback.Feedback())
+//
+// The handlers are added to the bot once the router is mounted, as if
+// they were handled by a Group with the router's middleware and error
+// handler. The router's middleware applies to all its handlers, no matter
+// whether it's added before or after them. A router can be mounted more
+// than once, and into other routers.
+type Router struct {
+	middleware []MiddlewareFunc
+	onError    func(error, Context)
+	routes     []func(*Group)
+}
+
+// NewRouter returns an empty router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Use adds middleware to the router's chain.
+func (r *Router) Use(middleware ...MiddlewareFunc) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// OnError sets the error handler of the router's handlers,
+// see Group.OnError.
+func (r *Router) OnError(onError func(error, Context)) {
+	r.onError = onError
+}
+
+// Handle adds the endpoint handler, see Bot.Handle.
+func (r *Router) Handle(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.Handle(endpoint, h, m...)
+	})
+}
+
+// HandleChats adds the endpoint handler restricted to the chat types,
+// see Bot.HandleChats.
+func (r *Router) HandleChats(types []ChatType, endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.HandleChats(types, endpoint, h, m...)
+	})
+}
+
+// HandlePrefix adds the prefix handler, see Bot.HandlePrefix.
+func (r *Router) HandlePrefix(prefix string, h HandlerFunc, m ...MiddlewareFunc) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.HandlePrefix(prefix, h, m...)
+	})
+}
+
+// HandleRegexp adds the regexp handler, see Bot.HandleRegexp.
+func (r *Router) HandleRegexp(pattern *regexp.Regexp, h HandlerFunc, m ...MiddlewareFunc) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.HandleRegexp(pattern, h, m...)
+	})
+}
+
+// HandleCallback adds the callback pattern handler, see Bot.HandleCallback.
+func (r *Router) HandleCallback(pattern string, h HandlerFunc, m ...MiddlewareFunc) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.HandleCallback(pattern, h, m...)
+	})
+}
+
+// Mount mounts the sub-router into the router, with the optional
+// prefix, see Bot.Mount. The sub-router runs the middleware of the
+// router first.
+func (r *Router) Mount(sub *Router, prefix ...string) {
+	r.routes = append(r.routes, func(g *Group) {
+		g.Mount(sub, prefix...)
+	})
+}
+
+// mount adds the router's handlers to the bot within the group.
+func (r *Router) mount(g *Group) {
+	g.Use(r.middleware...)
+	g.OnError(r.onError)
+	for _, route := range r.routes {
+		route(g)
+	}
+}
+
+// Mount adds the handlers of the router to the bot. The optional prefix
+// is added to the router's commands, callback uniques and callback
+// patterns, like the Group prefix does. The global middleware applies to them as well.
+func (b *Bot) Mount(r *Router, prefix ...string) {
+	r.mount(b.Group(prefix...))
+}
+
+// Mount adds the handlers of the router to the bot within a group
+// nested in this one, so the group's middleware runs first,
+// see Bot.Mount.
+func (g *Group) Mount(r *Router, prefix ...string) {
+	r.mount(g.Group(prefix...))
+}
@@ -0,0 +1,395 @@
+package telebot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RouteKind is a kind of handler a text message can be matched with.
+type RouteKind int
+
+const (
+	// RouteExact stands for commands and exact text endpoints.
+	RouteExact RouteKind = iota
+
+	// RoutePrefix stands for handlers matching the text prefix,
+	// see Bot.HandlePrefix.
+	RoutePrefix
+
+	// RouteRegex stands for handlers matching the text with a regexp.
+	RouteRegex
+
+	// RouteText stands for the OnText (and OnReply) handlers.
+	RouteText
+
+	// RouteUnhandled stands for the OnUnhandled handler.
+	RouteUnhandled
+
+	// RouteEvent stands for the rest of endpoints, which are
+	// dispatched by the update type and aren't prioritized.
+	RouteEvent
+)
+
+// String returns the kind name.
+func (k RouteKind) String() string {
+	switch k {
+	case RouteExact:
+		return "exact"
+	case RoutePrefix:
+		return "prefix"
+	case RouteRegex:
+		return "regex"
+	case RouteText:
+		return "text"
+	case RouteUnhandled:
+		return "unhandled"
+	case RouteEvent:
+		return "event"
+	default:
+		return fmt.Sprintf("RouteKind(%d)", int(k))
+	}
+}
+
+// DefaultHandlerPriority is the order in which the handlers are tried
+// for a text message. Only the first matching handler runs.
+var DefaultHandlerPriority = []RouteKind{
+	RouteExact,
+	RoutePrefix,
+	RouteRegex,
+	RouteText,
+	RouteUnhandled,
+}
+
+// Route describes a registered handler.
+type Route struct {
+	Endpoint string
+	Kind     RouteKind
+
+	// Priority is the effective priority of the route, the lower
+	// the earlier it's tried. It equals -1 for RouteEvent routes.
+	Priority int
+}
+
+// Routes returns the registered handlers sorted by their effective
+// priority, see Settings.HandlerPriority. Event routes, the callback
+// buttons included, go last. The prefix, regexp and callback pattern
+// routes are listed by their prefixes and patterns.
+func (b *Bot) Routes() []Route {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	routes := make([]Route, 0, len(b.handlers)+len(b.prefixes)+len(b.regexps)+len(b.patterns))
+	add := func(end string, kind RouteKind) {
+		priority := -1
+		for i, k := range b.priority {
+			if k == kind {
+				priority = i
+				break
+			}
+		}
+		routes = append(routes, Route{Endpoint: end, Kind: kind, Priority: priority})
+	}
+
+	for end := range b.handlers {
+		add(end, routeKind(end))
+	}
+	for end, routes := range b.chatRoutes {
+		for range routes {
+			add(end, routeKind(end))
+		}
+	}
+	for _, r := range b.prefixes {
+		add(r.prefix, RoutePrefix)
+	}
+	for _, r := range b.regexps {
+		add(r.pattern.String(), RouteRegex)
+	}
+	for _, r := range b.patterns {
+		add(r.pattern, RouteEvent)
+	}
+
+	// The prefix and regexp routes keep the order they are tried in.
+	sort.SliceStable(routes, func(i, j int) bool {
+		pi, pj := routes[i].Priority, routes[j].Priority
+		if pi != pj {
+			if pi < 0 || pj < 0 {
+				return pj < 0
+			}
+			return pi < pj
+		}
+		if k := routes[i].Kind; k == routes[j].Kind && (k == RoutePrefix || k == RouteRegex) {
+			return false
+		}
+		return routes[i].Endpoint < routes[j].Endpoint
+	})
+	return routes
+}
+
+// chatRoute is a handler registered with HandleChats.
+type chatRoute struct {
+	types   []ChatType
+	handler HandlerFunc
+}
+
+// HandleChats adds the endpoint handler restricted to the chat types.
+// In other chats, the endpoint is handled by the handler registered with
+// Handle, if any, or isn't matched at all, so the routing goes on:
+//
+//	b.HandleChats([]tele.ChatType{tele.ChatPrivate}, "/start", onStartPrivate)
+//	b.Handle("/start", onStart)
+//
+// The handlers of the same endpoint are tried in the order of registration.
+// The updates with no chat never match them. The aliases are supported
+// the same way as by Handle.
+func (b *Bot) HandleChats(types []ChatType, endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	if aliases, ok := endpoint.([]string); ok && len(aliases) > 0 {
+		for _, alias := range aliases {
+			b.HandleChats(types, alias, h, m...)
+		}
+		return
+	}
+
+	end := extractEndpoint(endpoint)
+	if end == "" {
+		panic("telebot: unsupported endpoint")
+	}
+	route := chatRoute{
+		types:   types,
+		handler: b.wrapHandler("handler:"+strings.TrimLeft(end, "\a\f"), h, m),
+	}
+
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+	if b.chatRoutes == nil {
+		b.chatRoutes = make(map[string][]chatRoute)
+	}
+	b.chatRoutes[end] = append(b.chatRoutes[end], route)
+}
+
+// HandlePrivate adds the endpoint handler for the private chats only,
+// see HandleChats.
+func (b *Bot) HandlePrivate(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	b.HandleChats([]ChatType{ChatPrivate}, endpoint, h, m...)
+}
+
+// HandleGroup adds the endpoint handler for the groups and supergroups
+// only, see HandleChats.
+func (b *Bot) HandleGroup(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	b.HandleChats([]ChatType{ChatGroup, ChatSuperGroup}, endpoint, h, m...)
+}
+
+// HandleChannel adds the endpoint handler for the channels only,
+// see HandleChats.
+func (b *Bot) HandleChannel(endpoint any, h HandlerFunc, m ...MiddlewareFunc) {
+	b.HandleChats([]ChatType{ChatChannel, ChatChannelPrivate}, endpoint, h, m...)
+}
+
+// lookup returns the handler of the endpoint for the context,
+// the ones restricted to its chat type first.
+func (b *Bot) lookup(end string, c Context) (HandlerFunc, bool) {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	if routes := b.chatRoutes[end]; len(routes) > 0 && c != nil {
+		if chat := c.Chat(); chat != nil {
+			for _, r := range routes {
+				for _, typ := range r.types {
+					if typ == chat.Type {
+						return r.handler, true
+					}
+				}
+			}
+		}
+	}
+	handler, ok := b.handlers[end]
+	return handler, ok
+}
+
+// hasHandler says whether any handler of the endpoint is registered,
+// the ones restricted to the chat types included.
+func (b *Bot) hasHandler(end string) bool {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	_, ok := b.handlers[end]
+	return ok || len(b.chatRoutes[end]) > 0
+}
+
+// Unhandle removes the handlers of the endpoint, so the features can be
+// turned off while the bot is running. It accepts the endpoints Handle and
+// HandleChats do, removing the handlers of both, a *regexp.Regexp to remove
+// the HandleRegexp handlers of the same expression, and a HandlePrefix
+// prefix or a HandleCallback pattern. Reports whether any handler was removed:
+//
+//	b.Handle("/poll", onPoll)
+//	...
+//	b.Unhandle("/poll")
+//
+// The updates being handled at the moment are not affected.
+func (b *Bot) Unhandle(endpoint any) bool {
+	b.routesMu.Lock()
+	defer b.routesMu.Unlock()
+
+	if rx, ok := endpoint.(*regexp.Regexp); ok {
+		n := len(b.regexps)
+		b.regexps = removeRoutes(b.regexps, func(r regexpRoute) bool {
+			return r.pattern.String() == rx.String()
+		})
+		return len(b.regexps) < n
+	}
+
+	ends, ok := endpoint.([]string)
+	if !ok {
+		ends = []string{extractEndpoint(endpoint)}
+	}
+
+	removed := false
+	for _, end := range ends {
+		if end == "" {
+			panic("telebot: unsupported endpoint")
+		}
+		if _, ok := b.handlers[end]; ok {
+			delete(b.handlers, end)
+			removed = true
+		}
+		if _, ok := b.chatRoutes[end]; ok {
+			delete(b.chatRoutes, end)
+			removed = true
+		}
+
+		n := len(b.prefixes) + len(b.patterns)
+		b.prefixes = removeRoutes(b.prefixes, func(r prefixRoute) bool {
+			return r.prefix == end
+		})
+		b.patterns = removeRoutes(b.patterns, func(r patternRoute) bool {
+			return r.pattern == end
+		})
+		removed = removed || len(b.prefixes)+len(b.patterns) < n
+	}
+	return removed
+}
+
+// removeRoutes returns a copy of the routes without the matching ones.
+// The routes are never changed in place, since the handling goroutines
+// may iterate over them without the lock.
+func removeRoutes[T any](routes []T, match func(T) bool) []T {
+	var kept []T
+	for _, r := range routes {
+		if !match(r) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// prefixRoute is a handler registered with HandlePrefix.
+type prefixRoute struct {
+	prefix  string
+	handler HandlerFunc
+}
+
+// handlePrefix runs the handler of the longest prefix of the text.
+func (b *Bot) handlePrefix(c Context, text string) bool {
+	b.routesMu.RLock()
+	prefixes := b.prefixes
+	b.routesMu.RUnlock()
+
+	for _, r := range prefixes {
+		if strings.HasPrefix(text, r.prefix) {
+			b.runHandler(r.handler, c)
+			return true
+		}
+	}
+	return false
+}
+
+// regexpRoute is a handler registered with HandleRegexp.
+type regexpRoute struct {
+	pattern *regexp.Regexp
+	handler HandlerFunc
+}
+
+func routeKind(end string) RouteKind {
+	switch {
+	case end == OnText || end == OnReply:
+		return RouteText
+	case end == OnUnhandled:
+		return RouteUnhandled
+	case strings.HasPrefix(end, "\a"), strings.HasPrefix(end, "\f"):
+		// The events and the callback buttons aren't matched by text.
+		return RouteEvent
+	default:
+		return RouteExact
+	}
+}
+
+func validatePriority(priority []RouteKind) error {
+	seen := make(map[RouteKind]bool, len(priority))
+	for _, k := range priority {
+		if k < RouteExact || k >= RouteEvent {
+			return fmt.Errorf("telebot: unsupported handler priority kind %s", k)
+		}
+		if seen[k] {
+			return fmt.Errorf("telebot: duplicate handler priority kind %s", k)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// handleText runs the highest-priority handler matching the text message.
+func (b *Bot) handleText(c Context, command string) {
+	m := c.Message()
+
+	for _, kind := range b.priority {
+		switch kind {
+		case RouteExact:
+			if command != "" && b.handle(command, c) {
+				return
+			}
+			// 1:1 satisfaction
+			if b.handle(m.Text, c) {
+				return
+			}
+		case RouteText:
+			replied := m.ReplyTo != nil && b.handle(OnReply, c)
+			if b.handle(OnText, c) || replied {
+				return
+			}
+		case RoutePrefix:
+			if b.handlePrefix(c, m.Text) {
+				return
+			}
+		case RouteRegex:
+			if b.handleRegexp(c, m.Text) {
+				return
+			}
+		case RouteUnhandled:
+			if b.handle(OnUnhandled, c) {
+				return
+			}
+		}
+	}
+}
+
+// handleRegexp runs the first regexp handler matching the text.
+func (b *Bot) handleRegexp(c Context, text string) bool {
+	b.routesMu.RLock()
+	regexps := b.regexps
+	b.routesMu.RUnlock()
+
+	for _, r := range regexps {
+		matches := r.pattern.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+		if nc, ok := c.(*nativeContext); ok {
+			nc.setMatches(matches)
+		}
+		b.runHandler(r.handler, c)
+		return true
+	}
+	return false
+}
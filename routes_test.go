@@ -0,0 +1,467 @@
+package telebot
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerPriority(t *testing.T) {
+	newBot := func(t *testing.T, priority []RouteKind) (*Bot, *[]string) {
+		b, err := NewBot(Settings{Synchronous: true, Offline: true, HandlerPriority: priority})
+		require.NoError(t, err)
+
+		var fired []string
+		for _, end := range []string{"/start", "hello", OnText, OnUnhandled} {
+			end := end
+			b.Handle(end, func(c Context) error {
+				fired = append(fired, end)
+				return nil
+			})
+		}
+		return b, &fired
+	}
+
+	t.Run("default", func(t *testing.T) {
+		b, fired := newBot(t, nil)
+
+		b.ProcessUpdate(Update{Message: &Message{Text: "/start"}})
+		b.ProcessUpdate(Update{Message: &Message{Text: "hello"}})
+		b.ProcessUpdate(Update{Message: &Message{Text: "world"}})
+		assert.Equal(t, []string{"/start", "hello", OnText}, *fired)
+
+		delete(b.handlers, OnText)
+		b.ProcessUpdate(Update{Message: &Message{Text: "world"}})
+		assert.Equal(t, OnUnhandled, (*fired)[3])
+	})
+
+	t.Run("override", func(t *testing.T) {
+		b, fired := newBot(t, []RouteKind{RouteText, RouteExact})
+
+		b.ProcessUpdate(Update{Message: &Message{Text: "/start"}})
+		assert.Equal(t, []string{OnText}, *fired)
+
+		delete(b.handlers, OnText)
+		b.ProcessUpdate(Update{Message: &Message{Text: "world"}})
+		assert.Len(t, *fired, 1)
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		b, _ := newBot(t, nil)
+		b.Handle(OnPhoto, func(c Context) error { return nil })
+
+		var ends []string
+		for _, r := range b.Routes() {
+			ends = append(ends, r.Endpoint)
+		}
+		assert.Equal(t, []string{"/start", "hello", OnText, OnUnhandled, OnPhoto}, ends)
+		assert.Equal(t, RouteEvent, b.Routes()[4].Kind)
+		assert.Equal(t, -1, b.Routes()[4].Priority)
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		newRegexBot := func(priority []RouteKind) (*Bot, *[]string) {
+			b, fired := newBot(t, priority)
+			b.HandleRegexp(regexp.MustCompile(`^/start (\w+)$`), func(c Context) error {
+				*fired = append(*fired, "ref "+c.Matches()[1])
+				return nil
+			})
+			b.HandleRegexp(regexp.MustCompile(`^/start`), func(c Context) error {
+				*fired = append(*fired, "never")
+				return nil
+			})
+			return b, fired
+		}
+
+		b, fired := newRegexBot([]RouteKind{RouteRegex, RouteExact, RouteText})
+		b.ProcessUpdate(Update{Message: &Message{Text: "/start abc"}})
+		b.ProcessUpdate(Update{Message: &Message{Text: "hello"}})
+		assert.Equal(t, []string{"ref abc", "hello"}, *fired)
+
+		// The command wins over the regexp matching the same text
+		b, fired = newRegexBot([]RouteKind{RouteExact, RouteRegex, RouteText})
+		b.ProcessUpdate(Update{Message: &Message{Text: "/start abc"}})
+		b.ProcessUpdate(Update{Message: &Message{Text: "/other abc"}})
+		assert.Equal(t, []string{"/start", OnText}, *fired)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewBot(Settings{Offline: true, HandlerPriority: []RouteKind{RouteText, RouteText}})
+		assert.Error(t, err)
+
+		_, err = NewBot(Settings{Offline: true, HandlerPriority: []RouteKind{RouteEvent}})
+		assert.Error(t, err)
+	})
+}
+
+func TestHandlePrefix(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	handler := func(name string) HandlerFunc {
+		return func(c Context) error {
+			fired = append(fired, name)
+			return nil
+		}
+	}
+	b.HandlePrefix("order ", handler("order"))
+	b.HandlePrefix("order #", handler("id"))
+	b.HandleRegexp(regexp.MustCompile(`^order`), handler("regexp"))
+	b.Handle("order list", handler("exact"))
+	b.Handle(&Btn{Unique: "order"}, handler("button"))
+
+	for _, text := range []string{"order #7", "order pizza", "order list", "orders"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text}})
+	}
+	assert.Equal(t, []string{"id", "order", "exact", "regexp"}, fired)
+
+	var routes []string
+	for _, r := range b.Routes() {
+		routes = append(routes, r.Kind.String()+" "+r.Endpoint)
+	}
+	assert.Equal(t, []string{
+		"exact order list",
+		"prefix order #",
+		"prefix order ",
+		"regex ^order",
+		"event \forder",
+	}, routes)
+
+	assert.True(t, b.Unhandle("order #"))
+	b.ProcessUpdate(Update{Message: &Message{Text: "order #7"}})
+	assert.Equal(t, "order", fired[len(fired)-1])
+}
+
+func TestHandleRegexp(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	b.Handle("order #1", func(c Context) error {
+		fired = append(fired, "exact")
+		return nil
+	})
+	b.HandleRegexp(regexp.MustCompile(`^order #(\d+)$`), func(c Context) error {
+		fired = append(fired, "order "+c.Matches()[1])
+		return nil
+	})
+	b.HandleRegexp(regexp.MustCompile(`^order`), func(c Context) error {
+		fired = append(fired, "any order")
+		return nil
+	})
+	b.Handle(OnText, func(c Context) error {
+		assert.Nil(t, c.Matches())
+		fired = append(fired, "text")
+		return nil
+	})
+
+	for _, text := range []string{"order #1", "order #42", "order soon", "hello"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text}})
+	}
+	assert.Equal(t, []string{"exact", "order 42", "any order", "text"}, fired)
+
+	routes := b.Routes()
+	require.Len(t, routes, 4)
+	assert.Equal(t, Route{Endpoint: "^order #(\\d+)$", Kind: RouteRegex, Priority: 2}, routes[1])
+}
+
+func TestGroups(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var trace []string
+	mark := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				trace = append(trace, name)
+				return next(c)
+			}
+		}
+	}
+	handler := func(name string) HandlerFunc {
+		return func(c Context) error {
+			trace = append(trace, name)
+			return nil
+		}
+	}
+
+	b.Use(mark("global"))
+	admin := b.Group("admin_")
+	admin.Use(mark("admin"))
+	admin.Handle("/ban", handler("ban"))
+	admin.Handle(&Btn{Unique: "unban"}, handler("unban"))
+	admin.Handle(OnPhoto, handler("photo"))
+
+	owner := admin.Group("owner_")
+	owner.Use(mark("owner"))
+	owner.Handle("/wipe", handler("wipe"), mark("local"))
+
+	b.ProcessUpdate(Update{Message: &Message{Text: "/admin_ban"}})
+	assert.Equal(t, []string{"global", "admin", "ban"}, trace)
+
+	trace = nil
+	b.ProcessUpdate(Update{Message: &Message{Text: "/admin_owner_wipe"}})
+	assert.Equal(t, []string{"global", "admin", "owner", "local", "wipe"}, trace)
+
+	trace = nil
+	b.ProcessUpdate(Update{Callback: &Callback{Data: "\fadmin_unban|1"}})
+	b.ProcessUpdate(Update{Message: &Message{Photo: &Photo{}}})
+	assert.Equal(t, []string{"global", "admin", "unban", "global", "admin", "photo"}, trace)
+
+	trace = nil
+	b.ProcessUpdate(Update{Message: &Message{Text: "/ban"}})
+	assert.Empty(t, trace)
+}
+
+func TestHandleCallback(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	b.HandleCallback("item:{id}:{action}", func(c Context) error {
+		fired = append(fired, c.Param("action")+" "+c.Param("id"))
+		return nil
+	})
+	b.HandleCallback("page.{n}", func(c Context) error {
+		fired = append(fired, "page "+c.Param("n")+c.Param("id"))
+		return nil
+	})
+	b.Handle(OnCallback, func(c Context) error {
+		assert.Empty(t, c.Param("id"))
+		fired = append(fired, "callback")
+		return nil
+	})
+
+	r := &ReplyMarkup{}
+	btn := r.DataPattern("Buy", "item:{id}:{action}", 42, "buy")
+	assert.Equal(t, "item:42:buy", btn.Data)
+	assert.Equal(t, "item:{id}:{action}", FillPattern("item:{id}:{action}"))
+
+	for _, data := range []string{btn.Data, "page.2", "pageX2", "item::buy"} {
+		b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	}
+	assert.Equal(t, []string{"buy 42", "page 2", "callback", "callback"}, fired)
+}
+
+func TestHandleChats(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	handler := func(name string) HandlerFunc {
+		return func(c Context) error {
+			fired = append(fired, name)
+			return nil
+		}
+	}
+	b.HandlePrivate("/start", handler("private"))
+	b.HandleGroup("/start", handler("group"))
+	b.HandleChannel(OnText, handler("channel"))
+	b.Handle(OnText, handler("text"))
+	b.HandleGroup("/rules", handler("rules"))
+
+	send := func(text string, typ ChatType) {
+		b.ProcessUpdate(Update{Message: &Message{Text: text, Chat: &Chat{ID: 1, Type: typ}}})
+	}
+	send("/start", ChatPrivate)
+	send("/start", ChatSuperGroup)
+	send("/start", ChatChannel)
+	send("/rules", ChatPrivate)
+	send("/rules", ChatGroup)
+	assert.Equal(t, []string{"private", "group", "channel", "text", "rules"}, fired)
+
+	// The button family is routed by the chat type as well.
+	fired = nil
+	b.HandlePrivate(&Btn{Unique: "page"}, handler("page"))
+	press := func(data string, typ ChatType) {
+		b.ProcessUpdate(Update{Callback: &Callback{Data: data, Message: &Message{Chat: &Chat{ID: 1, Type: typ}}}})
+	}
+	press("\fpage|1", ChatPrivate)
+	press("\fpage_next|2", ChatPrivate)
+	press("\fpage_next|2", ChatGroup)
+	assert.Equal(t, []string{"page", "page"}, fired)
+
+	assert.Len(t, b.Routes(), 6)
+}
+
+func TestGroupOnError(t *testing.T) {
+	var reported []string
+	report := func(name string) func(error, Context) {
+		return func(err error, c Context) {
+			reported = append(reported, name+": "+err.Error())
+		}
+	}
+
+	b, err := NewBot(Settings{Synchronous: true, Offline: true, OnError: report("bot")})
+	require.NoError(t, err)
+
+	fail := func(c Context) error { return errors.New(c.Text()) }
+	b.Handle("/casual", fail)
+
+	payments := b.Group()
+	payments.OnError(report("payments"))
+	payments.Handle("/pay", fail)
+	payments.Group().Handle("/refund", fail)
+
+	audit := payments.Group()
+	audit.OnError(report("audit"))
+	audit.Handle("/audit", fail)
+
+	for _, text := range []string{"/casual", "/pay", "/refund", "/audit"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text}})
+	}
+	assert.Equal(t, []string{
+		"bot: /casual",
+		"payments: /pay",
+		"payments: /refund",
+		"audit: /audit",
+	}, reported)
+}
+
+func TestHandleAliases(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	h := func(c Context) error {
+		fired = append(fired, c.Text())
+		return nil
+	}
+	b.Handle([]string{"/start", "/help", "/menu"}, h)
+	b.Group("admin_").Handle([]string{"/ban", "/kick"}, h)
+
+	for _, text := range []string{"/start", "/help", "/menu", "/admin_kick", "/kick"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text}})
+	}
+	assert.Equal(t, []string{"/start", "/help", "/menu", "/admin_kick"}, fired)
+
+	assert.Panics(t, func() { b.Handle([]string{}, h) })
+}
+
+func TestCaseInsensitiveCommands(t *testing.T) {
+	for _, fold := range []bool{false, true} {
+		b, err := NewBot(Settings{Synchronous: true, Offline: true, CaseInsensitiveCommands: fold})
+		require.NoError(t, err)
+
+		var payloads []string
+		b.Handle("/start", func(c Context) error {
+			payloads = append(payloads, c.Message().Payload)
+			return nil
+		})
+
+		for _, text := range []string{"/start a", "/Start b", "/START c"} {
+			b.ProcessUpdate(Update{Message: &Message{Text: text}})
+		}
+		if fold {
+			assert.Equal(t, []string{"a", "b", "c"}, payloads)
+		} else {
+			assert.Equal(t, []string{"a"}, payloads)
+		}
+	}
+}
+
+func TestUnhandle(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var fired []string
+	h := func(c Context) error {
+		fired = append(fired, c.Text())
+		return nil
+	}
+	rx := regexp.MustCompile(`^order \d+$`)
+	b.Handle([]string{"/start", "/help"}, h)
+	b.HandlePrivate("/start", h)
+	b.HandleRegexp(rx, h)
+
+	assert.True(t, b.Unhandle("/start"))
+	assert.False(t, b.Unhandle("/start"))
+	assert.True(t, b.Unhandle(regexp.MustCompile(rx.String())))
+	assert.Panics(t, func() { b.Unhandle(42) })
+
+	private := &Chat{Type: ChatPrivate}
+	for _, text := range []string{"/start", "/help", "order 1"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text, Chat: private}})
+	}
+	assert.Equal(t, []string{"/help"}, fired)
+	assert.Len(t, b.Routes(), 1)
+
+	// The handlers are changed while the updates are handled.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Handle("/poll", func(Context) error { return nil })
+				b.HandleRegexp(rx, func(Context) error { return nil })
+				b.Unhandle("/poll")
+				b.Unhandle(rx)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.ProcessUpdate(Update{Message: &Message{Text: "/poll", Chat: private}})
+				b.ProcessUpdate(Update{Message: &Message{Text: "order 1", Chat: private}})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMount(t *testing.T) {
+	b, err := NewBot(Settings{Synchronous: true, Offline: true})
+	require.NoError(t, err)
+
+	var (
+		fired  []string
+		failed []error
+	)
+	trace := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				fired = append(fired, name)
+				return next(c)
+			}
+		}
+	}
+	errFailed := errors.New("failed")
+
+	sub := NewRouter()
+	sub.Handle("/ban", func(c Context) error { return errFailed })
+	sub.HandleCallback("rate:{stars}", func(c Context) error {
+		fired = append(fired, c.Param("stars"))
+		return nil
+	})
+
+	r := NewRouter()
+	r.Handle("/feedback", func(c Context) error {
+		fired = append(fired, c.Text())
+		return nil
+	})
+	r.Use(trace("router"))
+	r.OnError(func(err error, c Context) { failed = append(failed, err) })
+	r.Mount(sub, "admin_")
+
+	b.Mount(r)
+	b.Group("v2_").Mount(r)
+
+	for _, text := range []string{"/feedback", "/v2_feedback", "/admin_ban", "/v2_admin_ban", "/ban"} {
+		b.ProcessUpdate(Update{Message: &Message{Text: text}})
+	}
+	assert.Equal(t, []string{"router", "/feedback", "router", "/v2_feedback", "router", "router"}, fired)
+	assert.Equal(t, []error{errFailed, errFailed}, failed)
+
+	// Callback patterns are prefixed like the uniques
+	fired = nil
+	for _, data := range []string{"v2_admin_rate:5", "admin_rate:4", "rate:3"} {
+		b.ProcessUpdate(Update{Callback: &Callback{Data: data}})
+	}
+	assert.Equal(t, []string{"router", "5", "router", "4"}, fired)
+}
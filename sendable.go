@@ -28,7 +28,12 @@ func (p *Photo) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"chat_id": to.Recipient(),
 		"caption": p.Caption,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
+	if p.CaptionAbove {
+		params["show_caption_above_media"] = "true"
+	}
 
 	msg, err := b.sendMedia(p, params, nil)
 	if err != nil {
@@ -38,6 +43,7 @@ func (p *Photo) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 	msg.Photo.File.stealRef(&p.File)
 	*p = *msg.Photo
 	p.Caption = msg.Caption
+	p.CaptionAbove = msg.CaptionAbove
 
 	return msg, nil
 }
@@ -51,7 +57,9 @@ func (a *Audio) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"title":     a.Title,
 		"file_name": a.FileName,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	if a.Duration != 0 {
 		params["duration"] = strconv.Itoa(a.Duration)
@@ -83,7 +91,9 @@ func (d *Document) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error
 		"caption":   d.Caption,
 		"file_name": d.FileName,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	if d.FileSize != 0 {
 		params["file_size"] = strconv.FormatInt(d.FileSize, 10)
@@ -117,7 +127,9 @@ func (s *Sticker) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error)
 		"chat_id": to.Recipient(),
 		"emoji":   s.Emoji,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	msg, err := b.sendMedia(s, params, nil)
 	if err != nil {
@@ -137,7 +149,12 @@ func (v *Video) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"caption":   v.Caption,
 		"file_name": v.FileName,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
+	if v.CaptionAbove {
+		params["show_caption_above_media"] = "true"
+	}
 
 	if v.Duration != 0 {
 		params["duration"] = strconv.Itoa(v.Duration)
@@ -161,6 +178,7 @@ func (v *Video) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		vid.File.stealRef(&v.File)
 		*v = *vid
 		v.Caption = msg.Caption
+		v.CaptionAbove = msg.CaptionAbove
 	} else if doc := msg.Document; doc != nil {
 		// If video has no sound, Telegram can turn it into Document (GIF)
 		doc.File.stealRef(&v.File)
@@ -180,7 +198,12 @@ func (a *Animation) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, erro
 		"caption":   a.Caption,
 		"file_name": a.FileName,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
+	if a.CaptionAbove {
+		params["show_caption_above_media"] = "true"
+	}
 
 	if a.Duration != 0 {
 		params["duration"] = strconv.Itoa(a.Duration)
@@ -215,6 +238,7 @@ func (a *Animation) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, erro
 	}
 
 	a.Caption = msg.Caption
+	a.CaptionAbove = msg.CaptionAbove
 	return msg, nil
 }
 
@@ -224,7 +248,9 @@ func (v *Voice) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"chat_id": to.Recipient(),
 		"caption": v.Caption,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	if v.Duration != 0 {
 		params["duration"] = strconv.Itoa(v.Duration)
@@ -246,7 +272,9 @@ func (v *VideoNote) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, erro
 	params := map[string]string{
 		"chat_id": to.Recipient(),
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	if v.Duration != 0 {
 		params["duration"] = strconv.Itoa(v.Duration)
@@ -283,7 +311,9 @@ func (x *Location) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error
 	if x.AlertRadius != 0 {
 		params["proximity_alert_radius"] = strconv.Itoa(x.Heading)
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("sendLocation", params)
 	if err != nil {
@@ -306,7 +336,9 @@ func (v *Venue) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"google_place_id":   v.GooglePlaceID,
 		"google_place_type": v.GooglePlaceType,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("sendVenue", params)
 	if err != nil {
@@ -318,9 +350,13 @@ func (v *Venue) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 
 // Send delivers invoice through bot b to recipient.
 func (i *Invoice) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
+	b.redactor.add(i.Token)
+
 	params := i.params()
 	params["chat_id"] = to.Recipient()
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("sendInvoice", params)
 	if err != nil {
@@ -341,18 +377,35 @@ func (p *Poll) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"allows_multiple_answers": strconv.FormatBool(p.MultipleAnswers),
 		"correct_option_id":       strconv.Itoa(p.CorrectOption),
 	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
 	if p.Explanation != "" {
 		params["explanation"] = p.Explanation
-		params["explanation_parse_mode"] = p.ParseMode
+		embedFormatting(params, "explanation_", p.ParseMode, p.Entities)
 	}
+	embedFormatting(params, "question_", p.QuestionParseMode, p.QuestionEntities)
+
 	if p.OpenPeriod != 0 {
 		params["open_period"] = strconv.Itoa(p.OpenPeriod)
 	} else if p.CloseUnixdate != 0 {
 		params["close_date"] = strconv.FormatInt(p.CloseUnixdate, 10)
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
-	opts, _ := json.Marshal(p.Options)
+	options := make([]inputPollOption, len(p.Options))
+	for i, o := range p.Options {
+		options[i] = inputPollOption{
+			Text:      o.Text,
+			ParseMode: o.ParseMode,
+			Entities:  o.Entities,
+		}
+	}
+
+	opts, _ := json.Marshal(options)
 	params["options"] = string(opts)
 
 	data, err := b.Raw("sendPoll", params)
@@ -369,7 +422,9 @@ func (d *Dice) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"chat_id": to.Recipient(),
 		"emoji":   string(d.Type),
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("sendDice", params)
 	if err != nil {
@@ -385,7 +440,9 @@ func (g *Game) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 		"chat_id":         to.Recipient(),
 		"game_short_name": g.Name,
 	}
-	b.embedSendOptions(params, opt)
+	if err := b.embedSendOptions(params, opt); err != nil {
+		return nil, err
+	}
 
 	data, err := b.Raw("sendGame", params)
 	if err != nil {
@@ -395,6 +452,16 @@ func (g *Game) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, error) {
 	return extractMessage(data)
 }
 
+// embedFormatting sets either <prefix>parse_mode or <prefix>entities param.
+func embedFormatting(params map[string]string, prefix string, mode ParseMode, entities []MessageEntity) {
+	if len(entities) > 0 {
+		data, _ := json.Marshal(entities)
+		params[prefix+"entities"] = string(data)
+	} else if mode != ModeDefault {
+		params[prefix+"parse_mode"] = mode
+	}
+}
+
 func thumbnailToFilemap(thumb *Photo) map[string]File {
 	if thumb != nil {
 		return map[string]File{"thumbnail": thumb.File}
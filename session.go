@@ -0,0 +1,192 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SessionStore keeps the sessions of the conversations, see
+// Context.Session. A session is kept as a single value by its key.
+//
+// By default, the sessions are kept in the bot Store, which is in-memory
+// unless Settings.Store is set, so they are lost on restart. Supply
+// a persistent store to keep them.
+type SessionStore interface {
+	// Get returns the session data by the key. It returns
+	// ErrSessionNotFound if the session is missing or expired.
+	Get(key string) ([]byte, error)
+
+	// Set stores the session data by the key for the given time.
+	Set(key string, data []byte, ttl time.Duration) error
+
+	// Delete removes the session by the key.
+	Delete(key string) error
+}
+
+// SessionStoreNamespace is the Store namespace of the sessions.
+const SessionStoreNamespace = "sessions"
+
+// NewSessionStore returns a SessionStore keeping the sessions
+// in the store under SessionStoreNamespace.
+func NewSessionStore(store Store) SessionStore {
+	return storeSessions{store}
+}
+
+type storeSessions struct {
+	store Store
+}
+
+func (s storeSessions) Get(key string) ([]byte, error) {
+	data, err := s.store.Get(SessionStoreNamespace, key)
+	if errors.Is(err, ErrStoreKeyNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	return data, err
+}
+
+func (s storeSessions) Set(key string, data []byte, ttl time.Duration) error {
+	return s.store.Set(SessionStoreNamespace, key, data, ttl)
+}
+
+func (s storeSessions) Delete(key string) error {
+	return s.store.Delete(SessionStoreNamespace, key)
+}
+
+// sessionStore returns the session store, and creates
+// the one backed by the bot Store if needed.
+func (b *Bot) sessionStore() SessionStore {
+	b.sessionsMu.Lock()
+	defer b.sessionsMu.Unlock()
+
+	if b.sessions == nil {
+		b.sessions = NewSessionStore(b.Store())
+	}
+	return b.sessions
+}
+
+// DefaultSessionKey returns the key of the session of the sender in the
+// chat, "<chat>:<sender>". It's the sender ID alone for the updates with
+// no chat, such as inline queries, and empty for the ones with no sender.
+func DefaultSessionKey(c Context) string {
+	sender := c.Sender()
+	if sender == nil {
+		return ""
+	}
+
+	key := strconv.FormatInt(sender.ID, 10)
+	if chat := c.Chat(); chat != nil {
+		key = strconv.FormatInt(chat.ID, 10) + ":" + key
+	}
+	return key
+}
+
+// Session is the data of a conversation kept between the updates,
+// see Context.Session. The values are stored as JSON. The changes
+// are saved once the handler returns. It's safe for concurrent use.
+type Session struct {
+	key string
+
+	mu      sync.Mutex
+	values  map[string]json.RawMessage
+	changed bool
+}
+
+func newSession(key string) *Session {
+	return &Session{key: key, values: make(map[string]json.RawMessage)}
+}
+
+// Key returns the key of the session in the store.
+func (s *Session) Key() string {
+	return s.key
+}
+
+// Get decodes the value by the key into dest, which must be
+// a pointer. It reports whether the value is set.
+func (s *Session) Get(key string, dest any) (bool, error) {
+	s.mu.Lock()
+	data, ok := s.values[key]
+	s.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return true, wrapError(err)
+	}
+	return true, nil
+}
+
+// Set sets the value by the key, it must be marshalable to JSON.
+func (s *Session) Set(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = data
+	s.changed = true
+	return nil
+}
+
+// Delete removes the value by the key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+// Clear removes all the values, the session is removed from the store.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.values)
+	s.changed = true
+}
+
+// load reads the session from the store.
+func (s *Session) load(store SessionStore) error {
+	data, err := store.Get(s.key)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.values); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// save writes the changed session to the store, the empty
+// one is removed from there.
+func (s *Session) save(store SessionStore, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.changed || s.key == "" {
+		return nil
+	}
+	s.changed = false
+
+	if len(s.values) == 0 {
+		return store.Delete(s.key)
+	}
+
+	data, err := json.Marshal(s.values)
+	if err != nil {
+		return wrapError(err)
+	}
+	return store.Set(s.key, data, ttl)
+}
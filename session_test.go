@@ -0,0 +1,109 @@
+package telebot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true})
+	require.NoError(t, err)
+
+	var counts []int
+	b.Handle("/count", func(c Context) error {
+		var n int
+		_, err := c.Session().Get("count", &n)
+		require.NoError(t, err)
+
+		n++
+		counts = append(counts, n)
+		return c.Session().Set("count", n)
+	})
+	b.Handle("/reset", func(c Context) error {
+		c.Session().Clear()
+		return nil
+	})
+
+	update := func(text string, chat int64) Update {
+		return Update{Message: &Message{Text: text, Chat: &Chat{ID: chat}, Sender: &User{ID: 7}}}
+	}
+
+	b.ProcessUpdate(update("/count", 1))
+	b.ProcessUpdate(update("/count", 1))
+	b.ProcessUpdate(update("/count", 2))
+	assert.Equal(t, []int{1, 2, 1}, counts)
+
+	data, err := b.sessionStore().Get("1:7")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"count":2}`, string(data))
+
+	b.ProcessUpdate(update("/reset", 1))
+	_, err = b.sessionStore().Get("1:7")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	b.ProcessUpdate(update("/count", 1))
+	assert.Equal(t, 1, counts[len(counts)-1])
+}
+
+type failingSessionStore struct {
+	SessionStore
+}
+
+func (failingSessionStore) Get(key string) ([]byte, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestSessionLoadError(t *testing.T) {
+	var errs []error
+	b, err := NewBot(Settings{
+		Offline:      true,
+		Synchronous:  true,
+		SessionStore: failingSessionStore{},
+		SessionKey:   func(Context) string { return "key" },
+		OnError:      func(err error, _ Context) { errs = append(errs, err) },
+	})
+	require.NoError(t, err)
+
+	b.Handle(OnText, func(c Context) error {
+		return c.Session().Set("value", 1)
+	})
+	b.ProcessUpdate(Update{Message: &Message{Text: "hi"}})
+
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "unavailable")
+}
+
+func TestLocale(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, Synchronous: true, DefaultLocale: "en"})
+	require.NoError(t, err)
+
+	var locales []string
+	b.Handle(OnText, func(c Context) error {
+		if c.Text() != "hi" {
+			require.NoError(t, c.SetLocale(c.Text()))
+		}
+		locales = append(locales, c.Locale())
+		return nil
+	})
+
+	send := func(text string, user *User) {
+		b.ProcessUpdate(Update{Message: &Message{Text: text, Chat: &Chat{ID: user.ID}, Sender: user}})
+	}
+
+	uk := &User{ID: 1, LanguageCode: "uk"}
+	send("hi", uk)
+	send("hi", &User{ID: 2})
+	send("de", uk)
+	send("hi", uk)
+
+	locale, err := b.UserLocale(uk)
+	require.NoError(t, err)
+	assert.Equal(t, "de", locale)
+
+	require.NoError(t, b.SetUserLocale(uk, ""))
+	send("hi", uk)
+	assert.Equal(t, []string{"uk", "en", "de", "de", "uk"}, locales)
+}
@@ -0,0 +1,223 @@
+package telebot
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Store is a key-value storage of the bot state, shared by the stateful
+// features of the bot, so the persistence is configured once. See
+// Settings.Store and Bot.Store.
+//
+// The keys are grouped by namespaces, one per feature, so they don't
+// collide. Implementations must be safe for concurrent use. A value set
+// with a zero TTL doesn't expire, and an expired value must be reported
+// as missing, even if it isn't removed yet.
+//
+// The default store keeps the values in memory, so they are lost on
+// restart. Supply a persistent store (Redis, SQL, etc.) to keep them.
+type Store interface {
+	// Get returns the value of the key, or ErrStoreKeyNotFound
+	// if the key is missing or expired.
+	Get(namespace, key string) ([]byte, error)
+
+	// Set stores the value of the key for the given time.
+	Set(namespace, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the key. Missing keys are ignored.
+	Delete(namespace, key string) error
+
+	// Expire changes the time the key is kept for, counting from now.
+	// Returns ErrStoreKeyNotFound if the key is missing or expired.
+	Expire(namespace, key string, ttl time.Duration) error
+}
+
+// Store returns the bot state store, see Settings.Store. If none
+// was configured, the in-memory one is created on the first call.
+func (b *Bot) Store() Store {
+	b.storeMu.Lock()
+	defer b.storeMu.Unlock()
+
+	if b.store == nil {
+		store := NewMemoryStore(0)
+		store.clock = b.clock
+		b.store = store
+	}
+	return b.store
+}
+
+// DefaultMaxStoreKeys is the default capacity of MemoryStore.
+const DefaultMaxStoreKeys = 100000
+
+// MemoryStore is a bounded in-memory Store. When it's full, the keys
+// closest to expiration are evicted first, and the ones that never
+// expire are evicted last.
+type MemoryStore struct {
+	max     int
+	clock   Clock
+	mu      sync.Mutex
+	entries map[storeKey]*storeEntry
+	expiry  storeExpiry
+}
+
+type storeKey struct {
+	namespace string
+	key       string
+}
+
+type storeEntry struct {
+	key     storeKey
+	value   []byte
+	expires time.Time // zero if never expires
+	index   int       // in the expiry heap
+}
+
+func (e storeEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !e.expires.After(now)
+}
+
+// NewMemoryStore creates a store keeping up to max keys,
+// DefaultMaxStoreKeys if max is zero.
+func NewMemoryStore(max int) *MemoryStore {
+	if max <= 0 {
+		max = DefaultMaxStoreKeys
+	}
+	return &MemoryStore{
+		max:     max,
+		clock:   realClock{},
+		entries: make(map[storeKey]*storeEntry),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(namespace, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := storeKey{namespace, key}
+	e, ok := s.entries[k]
+	if !ok {
+		return nil, ErrStoreKeyNotFound
+	}
+	if e.expired(s.clock.Now()) {
+		s.remove(e)
+		return nil, ErrStoreKeyNotFound
+	}
+	return e.value, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(namespace, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	k := storeKey{namespace, key}
+	if e, ok := s.entries[k]; ok {
+		e.value, e.expires = value, expiresAt(now, ttl)
+		heap.Fix(&s.expiry, e.index)
+		return nil
+	}
+
+	s.evict(now)
+	e := &storeEntry{key: k, value: value, expires: expiresAt(now, ttl)}
+	s.entries[k] = e
+	heap.Push(&s.expiry, e)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(namespace, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[storeKey{namespace, key}]; ok {
+		s.remove(e)
+	}
+	return nil
+}
+
+// Expire implements Store.
+func (s *MemoryStore) Expire(namespace, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	k := storeKey{namespace, key}
+	e, ok := s.entries[k]
+	if !ok {
+		return ErrStoreKeyNotFound
+	}
+	if e.expired(now) {
+		s.remove(e)
+		return ErrStoreKeyNotFound
+	}
+
+	e.expires = expiresAt(now, ttl)
+	heap.Fix(&s.expiry, e.index)
+	return nil
+}
+
+// evict makes room for a new key: it removes the expired keys,
+// or the one closest to expiration if there are none. The expired
+// keys are the closest, so they are found first. s.mu must be held.
+func (s *MemoryStore) evict(now time.Time) {
+	for len(s.expiry) > 0 && s.expiry[0].expired(now) {
+		s.remove(s.expiry[0])
+	}
+	if len(s.entries) >= s.max {
+		s.remove(s.expiry[0])
+	}
+}
+
+// remove deletes the entry. s.mu must be held.
+func (s *MemoryStore) remove(e *storeEntry) {
+	delete(s.entries, e.key)
+	heap.Remove(&s.expiry, e.index)
+}
+
+// storeExpiry is a heap of the store entries,
+// the one closest to expiration first.
+type storeExpiry []*storeEntry
+
+func (h storeExpiry) Len() int           { return len(h) }
+func (h storeExpiry) Less(i, j int) bool { return before(h[i].expires, h[j].expires) }
+
+func (h storeExpiry) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *storeExpiry) Push(x any) {
+	e := x.(*storeEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *storeExpiry) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+func expiresAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// before compares the expiration times, the zero one being the latest.
+func before(a, b time.Time) bool {
+	switch {
+	case a.IsZero():
+		return false
+	case b.IsZero():
+		return true
+	default:
+		return a.Before(b)
+	}
+}
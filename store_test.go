@@ -0,0 +1,77 @@
+package telebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	clock := &stubClock{now: time.Now()}
+	s := NewMemoryStore(3)
+	s.clock = clock
+
+	require.NoError(t, s.Set("a", "key", []byte("1"), 0))
+	require.NoError(t, s.Set("b", "key", []byte("2"), time.Minute))
+
+	data, err := s.Get("a", "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), data)
+
+	_, err = s.Get("c", "key")
+	assert.Equal(t, ErrStoreKeyNotFound, err)
+
+	require.NoError(t, s.Expire("b", "key", time.Hour))
+	clock.now = clock.now.Add(30 * time.Minute)
+	_, err = s.Get("b", "key")
+	require.NoError(t, err)
+
+	// The keys that never expire are evicted last
+	require.NoError(t, s.Set("c", "key", []byte("3"), 2*time.Hour))
+	require.NoError(t, s.Set("d", "key", []byte("4"), 2*time.Hour))
+	_, err = s.Get("b", "key")
+	assert.Equal(t, ErrStoreKeyNotFound, err)
+	_, err = s.Get("a", "key")
+	assert.NoError(t, err)
+
+	clock.now = clock.now.Add(3 * time.Hour)
+	_, err = s.Get("c", "key")
+	assert.Equal(t, ErrStoreKeyNotFound, err)
+	assert.Equal(t, ErrStoreKeyNotFound, s.Expire("d", "key", time.Hour))
+
+	require.NoError(t, s.Delete("a", "key"))
+	assert.Empty(t, s.entries)
+	assert.Empty(t, s.expiry)
+
+	// Overwriting a key reorders it
+	s = NewMemoryStore(2)
+	s.clock = clock
+	require.NoError(t, s.Set("a", "key", []byte("1"), time.Minute))
+	require.NoError(t, s.Set("b", "key", []byte("2"), time.Hour))
+	require.NoError(t, s.Set("a", "key", []byte("1"), 2*time.Hour))
+	require.NoError(t, s.Set("c", "key", []byte("3"), 3*time.Hour))
+	_, err = s.Get("b", "key")
+	assert.Equal(t, ErrStoreKeyNotFound, err)
+	assert.Len(t, s.expiry, 2)
+}
+
+func TestBotStore(t *testing.T) {
+	store := NewMemoryStore(0)
+	b, err := NewBot(Settings{Offline: true, Store: store})
+	require.NoError(t, err)
+	assert.Same(t, store, b.Store())
+
+	token, err := b.storeCallbackState("state")
+	require.NoError(t, err)
+
+	data, err := store.Get(CallbackStoreNamespace, token)
+	require.NoError(t, err)
+	assert.Equal(t, `"state"`, string(data))
+
+	b, err = NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+	assert.NotNil(t, b.Store())
+	assert.Same(t, b.Store(), b.Store())
+}
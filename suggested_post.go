@@ -0,0 +1,137 @@
+package telebot
+
+import (
+	"fmt"
+	"time"
+)
+
+// SuggestedPostCurrency is a currency of a suggested post price.
+type SuggestedPostCurrency = string
+
+const (
+	SuggestedPostStars SuggestedPostCurrency = "XTR"
+	SuggestedPostTON   SuggestedPostCurrency = "TON"
+)
+
+// SuggestedPostState is a state of a suggested post.
+type SuggestedPostState = string
+
+const (
+	SuggestedPostStatePending  SuggestedPostState = "pending"
+	SuggestedPostStateApproved SuggestedPostState = "approved"
+	SuggestedPostStateDeclined SuggestedPostState = "declined"
+)
+
+// Limits of the suggested post price and send date.
+const (
+	MinSuggestedPostStars = 5
+	MaxSuggestedPostStars = 100000
+
+	// TON amounts are in nanotoncoins.
+	MinSuggestedPostTON = 10000000
+	MaxSuggestedPostTON = 10000000000000
+
+	MinSuggestedPostDelay = 5 * time.Minute
+	MaxSuggestedPostDelay = 30 * 24 * time.Hour
+)
+
+// SuggestedPostPrice describes the price of a suggested post.
+type SuggestedPostPrice struct {
+	// Currency in which the post will be paid, see SuggestedPostCurrency.
+	Currency SuggestedPostCurrency `json:"currency"`
+
+	// The amount of the currency that will be paid for the post in
+	// the smallest units of the currency: Stars or nanotoncoins.
+	Amount int64 `json:"amount"`
+}
+
+// SuggestedPost is a send option describing the parameters of a post
+// suggested to a channel through its direct messages chat.
+type SuggestedPost struct {
+	// (Optional) Proposed price for the post. If the field is omitted,
+	// then the post is unpaid.
+	Price *SuggestedPostPrice `json:"price,omitempty"`
+
+	// (Optional) Proposed send date of the post in Unix. If omitted,
+	// the post can be published at any time within 30 days at the sole
+	// discretion of the user who approves it.
+	SendUnixtime int64 `json:"send_date,omitempty"`
+}
+
+// SendDate returns the proposed send date of the post in local time.
+func (p *SuggestedPost) SendDate() time.Time {
+	return time.Unix(p.SendUnixtime, 0)
+}
+
+func (p *SuggestedPost) validate(now time.Time) error {
+	if p.Price != nil {
+		var min, max int64
+		switch p.Price.Currency {
+		case SuggestedPostStars:
+			min, max = MinSuggestedPostStars, MaxSuggestedPostStars
+		case SuggestedPostTON:
+			min, max = MinSuggestedPostTON, MaxSuggestedPostTON
+		default:
+			return fmt.Errorf("telebot: unsupported suggested post currency %q", p.Price.Currency)
+		}
+		if p.Price.Amount < min || p.Price.Amount > max {
+			return fmt.Errorf("telebot: suggested post price must be within %d-%d %s", min, max, p.Price.Currency)
+		}
+	}
+
+	if p.SendUnixtime != 0 {
+		delay := p.SendDate().Sub(now)
+		if delay < MinSuggestedPostDelay || delay > MaxSuggestedPostDelay {
+			return fmt.Errorf("telebot: suggested post send date must be %v to %v in the future",
+				MinSuggestedPostDelay, MaxSuggestedPostDelay)
+		}
+	}
+
+	return nil
+}
+
+// SuggestedPostInfo contains information about a suggested post
+// the message is.
+type SuggestedPostInfo struct {
+	// State of the suggested post, see SuggestedPostState.
+	State SuggestedPostState `json:"state"`
+
+	// (Optional) Proposed price of the post.
+	Price *SuggestedPostPrice `json:"price,omitempty"`
+
+	// (Optional) Proposed send date of the post in Unix.
+	SendUnixtime int64 `json:"send_date,omitempty"`
+}
+
+// SendDate returns the proposed send date of the post in local time.
+func (i *SuggestedPostInfo) SendDate() time.Time {
+	return time.Unix(i.SendUnixtime, 0)
+}
+
+// SuggestedPostApproved represents a service message about
+// the approval of a suggested post.
+type SuggestedPostApproved struct {
+	// (Optional) Message containing the suggested post.
+	Message *Message `json:"suggested_post_message,omitempty"`
+
+	// (Optional) Amount paid for the post.
+	Price *SuggestedPostPrice `json:"price,omitempty"`
+
+	// Date when the post will be published in Unix.
+	SendUnixtime int64 `json:"send_date"`
+}
+
+// SendDate returns the date when the post will be published in local time.
+func (a *SuggestedPostApproved) SendDate() time.Time {
+	return time.Unix(a.SendUnixtime, 0)
+}
+
+// SuggestedPostDeclined represents a service message about
+// the rejection of a suggested post.
+type SuggestedPostDeclined struct {
+	// (Optional) Message containing the suggested post.
+	Message *Message `json:"suggested_post_message,omitempty"`
+
+	// (Optional) Comment with which the post was declined.
+	Comment string `json:"comment,omitempty"`
+}
@@ -0,0 +1,42 @@
+package telebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestedPostValidate(t *testing.T) {
+	stars := func(amount int64) *SuggestedPostPrice {
+		return &SuggestedPostPrice{Currency: SuggestedPostStars, Amount: amount}
+	}
+
+	assert.NoError(t, (&SuggestedPost{}).validate(time.Now()))
+	assert.NoError(t, (&SuggestedPost{Price: stars(100)}).validate(time.Now()))
+	assert.Error(t, (&SuggestedPost{Price: stars(1)}).validate(time.Now()))
+	assert.Error(t, (&SuggestedPost{Price: stars(MaxSuggestedPostStars + 1)}).validate(time.Now()))
+	assert.Error(t, (&SuggestedPost{Price: &SuggestedPostPrice{Currency: "USD", Amount: 100}}).validate(time.Now()))
+
+	ton := &SuggestedPostPrice{Currency: SuggestedPostTON, Amount: MinSuggestedPostTON}
+	assert.NoError(t, (&SuggestedPost{Price: ton}).validate(time.Now()))
+
+	in := func(d time.Duration) int64 { return time.Now().Add(d).Unix() }
+	assert.NoError(t, (&SuggestedPost{SendUnixtime: in(time.Hour)}).validate(time.Now()))
+	assert.Error(t, (&SuggestedPost{SendUnixtime: in(time.Minute)}).validate(time.Now()))
+	assert.Error(t, (&SuggestedPost{SendUnixtime: in(40 * 24 * time.Hour)}).validate(time.Now()))
+}
+
+func TestSuggestedPostOption(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+
+	post := &SuggestedPost{Price: &SuggestedPostPrice{Currency: SuggestedPostStars, Amount: 10}}
+	params := make(map[string]string)
+	b.embedSendOptions(params, b.extractOptions([]any{post}))
+	assert.JSONEq(t, `{"price":{"currency":"XTR","amount":10}}`, params["suggested_post_parameters"])
+
+	_, err = b.Send(&Chat{ID: 1}, "text", &SuggestedPost{Price: &SuggestedPostPrice{Currency: SuggestedPostStars}})
+	assert.Error(t, err)
+}
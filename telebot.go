@@ -29,11 +29,24 @@ package telebot
 import "errors"
 
 var (
-	ErrBadRecipient    = errors.New("telebot: recipient is nil")
-	ErrUnsupportedWhat = errors.New("telebot: unsupported what argument")
-	ErrCouldNotUpdate  = errors.New("telebot: could not fetch new updates")
-	ErrTrueResult      = errors.New("telebot: result is True")
-	ErrBadContext      = errors.New("telebot: context does not contain message")
+	ErrBadRecipient          = errors.New("telebot: recipient is nil")
+	ErrUnsupportedWhat       = errors.New("telebot: unsupported what argument")
+	ErrCouldNotUpdate        = errors.New("telebot: could not fetch new updates")
+	ErrTrueResult            = errors.New("telebot: result is True")
+	ErrQuoteNotFound         = errors.New("telebot: quote not found in the message")
+	ErrCallbackStateNotFound = errors.New("telebot: callback state not found")
+	ErrStoreKeyNotFound      = errors.New("telebot: key not found in store")
+	ErrSessionNotFound       = errors.New("telebot: session not found")
+	ErrBadContext            = errors.New("telebot: context does not contain message")
+	ErrNoDocument            = errors.New("telebot: message has no document")
+	ErrEntitiesParseMode     = errors.New("telebot: entities can't be combined with parse mode")
+	ErrBusinessCannotReply   = errors.New("telebot: business connection does not allow the bot to reply")
+	ErrCallbackExpired       = errors.New("telebot: callback query is expired")
+	ErrLoginHash             = errors.New("telebot: login widget data has invalid hash")
+	ErrLoginExpired          = errors.New("telebot: login widget data is outdated")
+	ErrStartPayloadTooLong   = errors.New("telebot: start payload is too long")
+	ErrStartPayloadInvalid   = errors.New("telebot: start payload is invalid")
+	ErrStartPayloadSignature = errors.New("telebot: start payload has invalid signature")
 )
 
 const DefaultApiURL = "https://api.telegram.org"
@@ -47,6 +60,7 @@ const (
 	OnText                 = "\atext"
 	OnForward              = "\aforward"
 	OnReply                = "\areply"
+	OnUnhandled            = "\aunhandled"
 	OnEdited               = "\aedited"
 	OnPhoto                = "\aphoto"
 	OnAudio                = "\aaudio"
@@ -69,6 +83,7 @@ const (
 	OnPinned               = "\apinned"
 	OnChannelPost          = "\achannel_post"
 	OnEditedChannelPost    = "\aedited_channel_post"
+	OnDirectMessage        = "\adirect_message"
 	OnTopicCreated         = "\atopic_created"
 	OnTopicReopened        = "\atopic_reopened"
 	OnTopicClosed          = "\atopic_closed"
@@ -77,6 +92,10 @@ const (
 	OnGeneralTopicUnhidden = "\ageneral_topic_unhidden"
 	OnWriteAccessAllowed   = "\awrite_access_allowed"
 
+	OnSuggestedPost         = "\asuggested_post"
+	OnSuggestedPostApproved = "\asuggested_post_approved"
+	OnSuggestedPostDeclined = "\asuggested_post_declined"
+
 	OnAddedToGroup      = "\aadded_to_group"
 	OnUserJoined        = "\auser_joined"
 	OnUserLeft          = "\auser_left"
@@ -89,6 +108,10 @@ const (
 	OnSuperGroupCreated = "\asupergroup_created"
 	OnChannelCreated    = "\achannel_created"
 
+	// OnAny receives every update no other handler matched,
+	// see Settings.OnUnmatched.
+	OnAny = "\aany"
+
 	// OnMigration happens when group switches to
 	// a supergroup. You might want to update
 	// your internal references to this chat
@@ -96,6 +119,7 @@ const (
 	OnMigration = "\amigration"
 
 	OnMedia           = "\amedia"
+	OnAlbum           = "\aalbum"
 	OnCallback        = "\acallback"
 	OnQuery           = "\aquery"
 	OnInlineResult    = "\ainline_result"
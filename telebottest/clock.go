@@ -0,0 +1,145 @@
+// Package telebottest provides utilities for testing bots.
+package telebottest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	tele "github.com/nullcache/telebotx"
+)
+
+// FakeClock is a tele.Clock that only moves forward
+// when it's advanced manually.
+//
+//	clock := telebottest.NewFakeClock(time.Now())
+//	b, _ := tele.NewBot(tele.Settings{Clock: clock, ...})
+//	...
+//	clock.Advance(time.Second)
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a new fake clock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock
+// is advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a timer firing once the clock is advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) tele.Timer {
+	return c.add(d, make(chan time.Time, 1), nil)
+}
+
+// AfterFunc returns a timer calling f once the clock is advanced
+// by d. Unlike time.AfterFunc, f is called synchronously by Advance.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) tele.Timer {
+	return c.add(d, nil, f)
+}
+
+// Timers returns the number of timers waiting to fire.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Advance moves the clock forward by d and fires the timers
+// that are due, in the order of their firing time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var due, rest []*fakeTimer
+	for _, t := range c.timers {
+		if t.at.After(now) {
+			rest = append(rest, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	c.timers = rest
+	c.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].at.Before(due[j].at)
+	})
+	for _, t := range due {
+		t.fire(now)
+	}
+}
+
+func (c *FakeClock) add(d time.Duration, ch chan time.Time, f func()) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), ch: ch, f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// remove deletes the timer from the waiting ones,
+// reporting whether it was waiting. c.mu must be held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, w := range c.timers {
+		if w == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	ch    chan time.Time
+	f     func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.remove(t)
+	t.at = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	return active
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.f != nil {
+		t.f()
+		return
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
@@ -0,0 +1,89 @@
+package telebottest
+
+import (
+	"testing"
+	"time"
+
+	tele "github.com/nullcache/telebotx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ tele.Clock = (*FakeClock)(nil)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	c := NewFakeClock(start)
+
+	var fired []int
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, 2) })
+	c.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+	stopped := c.AfterFunc(time.Second, func() { fired = append(fired, 0) })
+	after := c.After(3 * time.Second)
+
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+	assert.Equal(t, 3, c.Timers())
+
+	c.Advance(2 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), c.Now())
+	assert.Equal(t, []int{1, 2}, fired)
+
+	select {
+	case <-after:
+		t.Fatal("timer fired too early")
+	default:
+	}
+
+	c.Advance(time.Second)
+	assert.Equal(t, start.Add(3*time.Second), <-after)
+	assert.Zero(t, c.Timers())
+}
+
+func TestFakeClockBot(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	b, err := tele.NewBot(tele.Settings{
+		Offline:      true,
+		Synchronous:  true,
+		AlbumTimeout: time.Second,
+		Clock:        clock,
+	})
+	require.NoError(t, err)
+
+	var album []*tele.Message
+	b.Handle(tele.OnAlbum, func(c tele.Context) error {
+		album = c.AlbumMessages()
+		return nil
+	})
+
+	b.ProcessUpdate(tele.Update{Message: &tele.Message{ID: 1, AlbumID: "1", Photo: &tele.Photo{}}})
+	clock.Advance(time.Second / 2)
+	b.ProcessUpdate(tele.Update{Message: &tele.Message{ID: 2, AlbumID: "1", Photo: &tele.Photo{}}})
+	clock.Advance(time.Second / 2)
+	assert.Nil(t, album)
+
+	clock.Advance(time.Second / 2)
+	assert.Len(t, album, 2)
+}
+
+func TestLongOpClock(t *testing.T) {
+	rec := NewRecorder()
+	defer rec.Close()
+
+	clock := NewFakeClock(time.Now())
+	settings := rec.Settings()
+	settings.Clock = clock
+	b, err := tele.NewBot(settings)
+	require.NoError(t, err)
+
+	c := NewContext(b).Context()
+	_, done := c.LongOp(tele.Typing, 0)
+	defer done()
+
+	for n := 1; n <= 3; n++ {
+		require.Eventually(t, func() bool {
+			return len(rec.Calls()) == n && clock.Timers() == 1
+		}, time.Second, time.Millisecond)
+		clock.Advance(4 * time.Second)
+	}
+}
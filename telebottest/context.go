@@ -0,0 +1,118 @@
+package telebottest
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	tele "github.com/nullcache/telebotx"
+)
+
+var lastID atomic.Int64
+
+func nextID() int {
+	return int(lastID.Add(1))
+}
+
+// ContextBuilder builds the updates for the handler tests, so they don't
+// need to be crafted by hand. The builder methods modify and return it:
+//
+//	c := telebottest.NewContext(b).WithText("/start ref").WithSender(42).Context()
+//	require.NoError(t, onStart(c))
+//
+// By default, the message is sent to the private chat with the sender.
+type ContextBuilder struct {
+	bot      *tele.Bot
+	text     string
+	sender   *tele.User
+	chat     *tele.Chat
+	callback *string
+}
+
+// NewContext starts building the update for the bot.
+func NewContext(bot *tele.Bot) *ContextBuilder {
+	return &ContextBuilder{bot: bot}
+}
+
+// WithText sets the text of the message, commands included.
+func (cb *ContextBuilder) WithText(text string) *ContextBuilder {
+	cb.text = text
+	return cb
+}
+
+// WithSender sets the ID of the sender.
+func (cb *ContextBuilder) WithSender(id int64) *ContextBuilder {
+	cb.sender = &tele.User{ID: id}
+	return cb
+}
+
+// WithUser sets the sender.
+func (cb *ContextBuilder) WithUser(user *tele.User) *ContextBuilder {
+	cb.sender = user
+	return cb
+}
+
+// WithChat sets the chat of the message.
+func (cb *ContextBuilder) WithChat(chat *tele.Chat) *ContextBuilder {
+	cb.chat = chat
+	return cb
+}
+
+// WithCallback turns the update into the callback query with the data,
+// pressed on the message built so far. Use "\f<unique>|<data>" data to
+// reach the handler of the &tele.Btn{Unique: unique} endpoint.
+func (cb *ContextBuilder) WithCallback(data string) *ContextBuilder {
+	cb.callback = &data
+	return cb
+}
+
+// Update returns the built update.
+func (cb *ContextBuilder) Update() tele.Update {
+	sender := cb.sender
+	if sender == nil {
+		sender = &tele.User{ID: 1}
+	}
+	chat := cb.chat
+	if chat == nil {
+		chat = &tele.Chat{ID: sender.ID, Type: tele.ChatPrivate}
+	}
+
+	m := &tele.Message{
+		ID:       nextID(),
+		Sender:   sender,
+		Chat:     chat,
+		Unixtime: time.Now().Unix(),
+		Text:     cb.text,
+	}
+
+	u := tele.Update{ID: nextID()}
+	if cb.callback != nil {
+		u.Callback = &tele.Callback{
+			ID:      strconv.Itoa(u.ID),
+			Sender:  sender,
+			Message: m,
+			Data:    *cb.callback,
+		}
+	} else {
+		u.Message = m
+	}
+	return u
+}
+
+// Context returns the context of the built update, without running
+// the handlers. The command payload is parsed like the bot does it.
+func (cb *ContextBuilder) Context() tele.Context {
+	u := cb.Update()
+	if m := u.Message; m != nil {
+		if _, _, payload, ok := tele.SplitCommand(m.Text); ok {
+			m.Payload = payload
+		}
+	}
+	return cb.bot.NewContext(u)
+}
+
+// Process passes the built update to the bot handlers. Set
+// Settings.Synchronous to have them done once it returns.
+func (cb *ContextBuilder) Process() {
+	cb.bot.ProcessUpdate(cb.Update())
+}
@@ -0,0 +1,73 @@
+package telebottest
+
+import (
+	"strings"
+	"testing"
+
+	tele "github.com/nullcache/telebotx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextBuilder(t *testing.T) {
+	rec := NewRecorder()
+	defer rec.Close()
+
+	b, err := tele.NewBot(rec.Settings())
+	require.NoError(t, err)
+
+	b.Handle("/start", func(c tele.Context) error {
+		return c.Send("Welcome, " + c.Message().Payload)
+	})
+	b.Handle(&tele.Btn{Unique: "like"}, func(c tele.Context) error {
+		if err := c.Respond(); err != nil {
+			return err
+		}
+		return c.Send("Liked " + c.Data())
+	})
+
+	NewContext(b).WithText("/start ref").WithSender(42).Process()
+	NewContext(b).WithSender(42).WithCallback("\flike|7").Process()
+	assert.Equal(t, []string{"Welcome, ref", "Liked 7"}, rec.Texts())
+
+	calls := rec.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "42", calls[0].Params["chat_id"])
+	assert.Equal(t, "answerCallbackQuery", calls[1].Method)
+
+	rec.Reset()
+	c := NewContext(b).WithText("/ban 7 1h").WithChat(&tele.Chat{ID: -100, Type: tele.ChatGroup}).Context()
+	assert.Equal(t, []string{"7", "1h"}, c.Args())
+	assert.Equal(t, "ref", NewContext(b).WithText("/start\nref").Context().Message().Payload)
+	assert.Equal(t, int64(-100), c.Chat().ID)
+	assert.Equal(t, int64(1), c.Sender().ID)
+
+	require.NoError(t, c.Send(&tele.Photo{File: tele.FromReader(strings.NewReader("png")), Caption: "photo"}))
+	sent := rec.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "sendPhoto", sent[0].Method)
+	assert.Equal(t, "photo", sent[0].Params["caption"])
+}
+
+func TestRecorderAlbum(t *testing.T) {
+	rec := NewRecorder()
+	defer rec.Close()
+
+	b, err := tele.NewBot(rec.Settings())
+	require.NoError(t, err)
+
+	c := NewContext(b).Context()
+	msgs, err := c.SendAlbum(tele.Album{
+		&tele.Photo{File: tele.FromReader(strings.NewReader("png")), Caption: "first"},
+		&tele.Video{File: tele.FromReader(strings.NewReader("mp4"))},
+	})
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "first", msgs[0].Caption)
+	assert.NotNil(t, msgs[0].Photo)
+	assert.NotNil(t, msgs[1].Video)
+
+	sent := rec.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "sendMediaGroup", sent[0].Method)
+}
@@ -0,0 +1,220 @@
+package telebottest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tele "github.com/nullcache/telebotx"
+)
+
+// Call is a Bot API request captured by Recorder.
+type Call struct {
+	// Method is the name of the API method, e.g. "sendMessage".
+	Method string
+
+	// Params are the request parameters. The uploaded files
+	// are recorded by their file names.
+	Params map[string]string
+}
+
+// Recorder is a fake Bot API server capturing the requests of the bot.
+// The send methods are answered with a message made of the request, and
+// the rest with true:
+//
+//	rec := telebottest.NewRecorder()
+//	defer rec.Close()
+//
+//	b, _ := tele.NewBot(rec.Settings())
+//	b.Handle("/start", onStart)
+//	telebottest.NewContext(b).WithText("/start").Process()
+//
+//	assert.Equal(t, []string{"Welcome!"}, rec.Texts())
+type Recorder struct {
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder starts the fake server. Close it once done.
+func NewRecorder() *Recorder {
+	r := &Recorder{}
+	r.srv = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+// URL returns the API URL of the fake server.
+func (r *Recorder) URL() string {
+	return r.srv.URL
+}
+
+// Settings returns the settings of the synchronous offline
+// bot making the requests to the fake server.
+func (r *Recorder) Settings() tele.Settings {
+	return tele.Settings{
+		URL:         r.srv.URL,
+		Token:       "test",
+		Offline:     true,
+		Synchronous: true,
+	}
+}
+
+// Close shuts the fake server down.
+func (r *Recorder) Close() {
+	r.srv.Close()
+}
+
+// Calls returns the captured requests in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Sent returns the captured requests of the send methods,
+// such as sendMessage and sendPhoto.
+func (r *Recorder) Sent() []Call {
+	var sent []Call
+	for _, call := range r.Calls() {
+		if isSend(call.Method) {
+			sent = append(sent, call)
+		}
+	}
+	return sent
+}
+
+// Texts returns the texts and captions of the sent messages.
+func (r *Recorder) Texts() []string {
+	var texts []string
+	for _, call := range r.Sent() {
+		if text, ok := call.Params["text"]; ok {
+			texts = append(texts, text)
+		} else if caption, ok := call.Params["caption"]; ok {
+			texts = append(texts, caption)
+		}
+	}
+	return texts
+}
+
+// Reset forgets the captured requests.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) serve(w http.ResponseWriter, req *http.Request) {
+	method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	params := readParams(req)
+
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Params: params})
+	id := len(r.calls)
+	r.mu.Unlock()
+
+	var result any = true
+	switch {
+	case method == "sendMediaGroup":
+		// The album is answered with a message per media.
+		var media []struct {
+			Type    string `json:"type"`
+			Caption string `json:"caption"`
+		}
+		json.Unmarshal([]byte(params["media"]), &media)
+
+		msgs := make([]any, len(media))
+		for i, m := range media {
+			msg := fakeMessage(id, params["chat_id"], "", m.Caption)
+			addMedia(msg, id, m.Type)
+			msgs[i] = msg
+		}
+		result = msgs
+	case isSend(method):
+		msg := fakeMessage(id, params["chat_id"], params["text"], params["caption"])
+		addMedia(msg, id, mediaType[method])
+		result = msg
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+// fakeMessage returns the message answering the send request.
+func fakeMessage(id int, chat, text, caption string) map[string]any {
+	chatID, _ := strconv.ParseInt(chat, 10, 64)
+	return map[string]any{
+		"message_id": id,
+		"date":       time.Now().Unix(),
+		"chat":       map[string]any{"id": chatID},
+		"text":       text,
+		"caption":    caption,
+	}
+}
+
+// addMedia adds a fake file of the media type to the message,
+// so the sent media is answered with its file.
+func addMedia(msg map[string]any, id int, typ string) {
+	file := map[string]any{"file_id": "file" + strconv.Itoa(id), "file_unique_id": strconv.Itoa(id)}
+	switch typ {
+	case "":
+	case "photo":
+		msg["photo"] = []any{file}
+	default:
+		msg[typ] = file
+	}
+}
+
+var mediaType = map[string]string{
+	"sendPhoto":     "photo",
+	"sendAudio":     "audio",
+	"sendDocument":  "document",
+	"sendVideo":     "video",
+	"sendAnimation": "animation",
+	"sendVoice":     "voice",
+	"sendVideoNote": "video_note",
+	"sendSticker":   "sticker",
+}
+
+func isSend(method string) bool {
+	return strings.HasPrefix(method, "send") && method != "sendChatAction" ||
+		method == "copyMessage" || method == "forwardMessage"
+}
+
+// readParams reads the JSON or multipart request parameters.
+func readParams(req *http.Request) map[string]string {
+	params := make(map[string]string)
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		data, _ := io.ReadAll(req.Body)
+
+		var raw map[string]any
+		json.Unmarshal(data, &raw)
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				params[k] = s
+			} else {
+				data, _ := json.Marshal(v)
+				params[k] = string(data)
+			}
+		}
+		return params
+	}
+
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		return params
+	}
+	for k, v := range req.MultipartForm.Value {
+		params[k] = v[0]
+	}
+	for k, v := range req.MultipartForm.File {
+		params[k] = v[0].Filename
+	}
+	return params
+}
@@ -0,0 +1,175 @@
+package telebot
+
+import (
+	"errors"
+	"strings"
+)
+
+// TextBuilder builds a message text along with its entities, counting
+// the offsets in UTF-16 code units as Telegram does. Send the result
+// with its Entities instead of a parse mode:
+//
+//	tb := &tele.TextBuilder{}
+//	tb.Text("Quote of the day:\n").Blockquote("Stay hungry, stay foolish.")
+//	text, entities, err := tb.Build()
+//	...
+//	b.Send(to, text, entities)
+//
+// The first error stops the building and is returned by Build.
+type TextBuilder struct {
+	text     strings.Builder
+	size     int
+	entities Entities
+	quoted   bool
+	err      error
+}
+
+// Text appends the plain text.
+func (tb *TextBuilder) Text(s string) *TextBuilder {
+	if tb.err == nil {
+		tb.text.WriteString(s)
+		tb.size += utf16Len(s)
+	}
+	return tb
+}
+
+// Entity appends the text as an entity of the given type, such as
+// EntityBold or EntityCode. Use the dedicated methods for the
+// entities which need extra fields or checks.
+func (tb *TextBuilder) Entity(typ EntityType, s string) *TextBuilder {
+	switch typ {
+	case EntityCustomEmoji, EntityBlockquote, EntityEBlockquote:
+		return tb.fail(errors.New("telebot: use the dedicated builder method for " + string(typ)))
+	}
+	return tb.add(MessageEntity{Type: typ}, s)
+}
+
+// CustomEmoji appends the custom emoji, which is shown with the
+// placeholder emoji where custom emoji aren't available.
+func (tb *TextBuilder) CustomEmoji(placeholder, id string) *TextBuilder {
+	if id == "" {
+		return tb.fail(errors.New("telebot: custom emoji id is empty"))
+	}
+	if !isEmoji(placeholder) {
+		return tb.fail(errors.New("telebot: custom emoji placeholder must be an emoji"))
+	}
+	return tb.add(MessageEntity{Type: EntityCustomEmoji, CustomEmojiID: id}, placeholder)
+}
+
+// Blockquote appends the text as a block quotation.
+func (tb *TextBuilder) Blockquote(text string) *TextBuilder {
+	return tb.BlockquoteFunc(EntityBlockquote, func(tb *TextBuilder) { tb.Text(text) })
+}
+
+// ExpandableBlockquote appends the text as a block quotation,
+// collapsed by default.
+func (tb *TextBuilder) ExpandableBlockquote(text string) *TextBuilder {
+	return tb.BlockquoteFunc(EntityEBlockquote, func(tb *TextBuilder) { tb.Text(text) })
+}
+
+// BlockquoteFunc appends a block quotation of the given type, either
+// EntityBlockquote or EntityEBlockquote, with the content built by f.
+// Telegram rejects nested quotations, so f must not add another one.
+func (tb *TextBuilder) BlockquoteFunc(typ EntityType, f func(tb *TextBuilder)) *TextBuilder {
+	if typ != EntityBlockquote && typ != EntityEBlockquote {
+		return tb.fail(errors.New("telebot: unsupported blockquote type " + string(typ)))
+	}
+	if tb.quoted {
+		return tb.fail(errors.New("telebot: nested blockquotes are not allowed"))
+	}
+	if tb.err != nil {
+		return tb
+	}
+
+	i, offset := len(tb.entities), tb.size
+	tb.entities = append(tb.entities, MessageEntity{Type: typ, Offset: offset})
+
+	tb.quoted = true
+	f(tb)
+	tb.quoted = false
+
+	if tb.err == nil {
+		tb.entities[i].Length = tb.size - offset
+	}
+	return tb
+}
+
+// String returns the text built so far.
+func (tb *TextBuilder) String() string {
+	return tb.text.String()
+}
+
+// Build returns the text and its entities, or the first error occurred.
+func (tb *TextBuilder) Build() (string, Entities, error) {
+	if tb.err != nil {
+		return "", nil, tb.err
+	}
+
+	entities := make(Entities, 0, len(tb.entities))
+	for _, e := range tb.entities {
+		if e.Length > 0 {
+			entities = append(entities, e)
+		}
+	}
+	return tb.text.String(), entities, nil
+}
+
+func (tb *TextBuilder) add(e MessageEntity, s string) *TextBuilder {
+	if tb.err != nil {
+		return tb
+	}
+
+	e.Offset = tb.size
+	e.Length = utf16Len(s)
+	tb.entities = append(tb.entities, e)
+	return tb.Text(s)
+}
+
+func (tb *TextBuilder) fail(err error) *TextBuilder {
+	if tb.err == nil {
+		tb.err = err
+	}
+	return tb
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// isEmoji reports whether s looks like a single emoji, possibly
+// composed of several code points, such as flags or ZWJ sequences.
+func isEmoji(s string) bool {
+	var (
+		pictograph bool
+		keycap     = strings.ContainsRune(s, 0x20E3)
+	)
+	for _, r := range s {
+		switch {
+		case r == 0x200D, r == 0xFE0E, r == 0xFE0F, r == 0x20E3,
+			r >= 0xE0020 && r <= 0xE007F: // joiners, selectors and tags
+		case keycap && (r == '#' || r == '*' || r >= '0' && r <= '9'):
+			pictograph = true
+		case r >= 0x1F000 && r <= 0x1FAFF, // pictographs, flags, skin tones
+			r >= 0x2190 && r <= 0x21FF, // arrows
+			r >= 0x2300 && r <= 0x23FF, // technical
+			r >= 0x2460 && r <= 0x27BF, // enclosed, shapes, dingbats
+			r >= 0x2900 && r <= 0x297F, // supplemental arrows
+			r >= 0x2B00 && r <= 0x2BFF, // miscellaneous symbols and arrows
+			r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049,
+			r == 0x2122, r == 0x2139, r == 0x3030, r == 0x303D,
+			r == 0x3297, r == 0x3299:
+			pictograph = true
+		default:
+			return false
+		}
+	}
+	return pictograph
+}
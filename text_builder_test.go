@@ -0,0 +1,50 @@
+package telebot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextBuilder(t *testing.T) {
+	tb := &TextBuilder{}
+	tb.Text("Hi 👋 ").
+		CustomEmoji("🔥", "123").
+		Text("\n").
+		BlockquoteFunc(EntityBlockquote, func(tb *TextBuilder) {
+			tb.Text("said ").Entity(EntityBold, "ü")
+		}).
+		Text("\n").
+		ExpandableBlockquote("more")
+
+	text, entities, err := tb.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "Hi 👋 🔥\nsaid ü\nmore", text)
+	assert.Equal(t, Entities{
+		{Type: EntityCustomEmoji, Offset: 6, Length: 2, CustomEmojiID: "123"},
+		{Type: EntityBlockquote, Offset: 9, Length: 6},
+		{Type: EntityBold, Offset: 14, Length: 1},
+		{Type: EntityEBlockquote, Offset: 16, Length: 4},
+	}, entities)
+
+	for _, placeholder := range []string{"👍🏽", "🇺🇦", "1️⃣", "❤️", "👨‍👩‍👧"} {
+		_, _, err := (&TextBuilder{}).CustomEmoji(placeholder, "1").Build()
+		assert.NoError(t, err, placeholder)
+	}
+	for _, placeholder := range []string{"", "a", "1", "🔥x"} {
+		_, _, err := (&TextBuilder{}).CustomEmoji(placeholder, "1").Build()
+		assert.Error(t, err, placeholder)
+	}
+
+	_, _, err = (&TextBuilder{}).CustomEmoji("🔥", "").Build()
+	assert.Error(t, err)
+
+	_, _, err = (&TextBuilder{}).BlockquoteFunc(EntityBlockquote, func(tb *TextBuilder) {
+		tb.Blockquote("nested")
+	}).Build()
+	assert.ErrorContains(t, err, "nested")
+
+	_, _, err = (&TextBuilder{}).Entity(EntityBlockquote, "quote").Build()
+	assert.Error(t, err)
+}
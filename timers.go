@@ -0,0 +1,76 @@
+package telebot
+
+import (
+	"time"
+)
+
+// botTimer is a Timer of the bot, which is stopped along with
+// the bot, see Bot.afterFunc.
+type botTimer struct {
+	Timer
+	b *Bot
+}
+
+// afterFunc calls f in its own goroutine once the duration elapses,
+// unless the bot is stopped before. Unlike the handlers, f isn't
+// bound to any update, so it may run long after the handler returned.
+func (b *Bot) afterFunc(d time.Duration, f func()) Timer {
+	t := &botTimer{b: b}
+
+	b.timersMu.Lock()
+	defer b.timersMu.Unlock()
+
+	t.Timer = b.clock.AfterFunc(d, func() {
+		b.timersMu.Lock()
+		_, pending := b.timers[t]
+		delete(b.timers, t)
+		b.timersMu.Unlock()
+
+		if pending {
+			f()
+		}
+	})
+	if b.timers == nil {
+		b.timers = make(map[*botTimer]struct{})
+	}
+	b.timers[t] = struct{}{}
+	return t
+}
+
+// Stop prevents the timer from firing.
+func (t *botTimer) Stop() bool {
+	t.b.timersMu.Lock()
+	delete(t.b.timers, t)
+	t.b.timersMu.Unlock()
+	return t.Timer.Stop()
+}
+
+// Reset changes the timer to expire after duration d.
+func (t *botTimer) Reset(d time.Duration) bool {
+	t.b.timersMu.Lock()
+	t.b.timers[t] = struct{}{}
+	t.b.timersMu.Unlock()
+	return t.Timer.Reset(d)
+}
+
+// stopTimers stops the pending timers, see Bot.Stop.
+func (b *Bot) stopTimers() {
+	b.timersMu.Lock()
+	defer b.timersMu.Unlock()
+
+	for t := range b.timers {
+		t.Timer.Stop()
+	}
+	clear(b.timers)
+}
+
+// DeleteAfter deletes the message once the duration elapses. The error
+// is passed to OnError. The deletion is canceled if the bot is stopped
+// before, or with Stop of the returned Timer.
+func (b *Bot) DeleteAfter(msg Editable, d time.Duration) Timer {
+	return b.afterFunc(d, func() {
+		if err := b.Delete(msg); err != nil {
+			b.OnError(err, nil)
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package telebot
 
-import "strings"
+import (
+	"context"
+	"errors"
+	"strings"
+)
 
 // Update object represents an incoming update.
 type Update struct {
@@ -30,15 +34,113 @@ type Update struct {
 	DeletedBusinessMessages *BusinessMessagesDeleted `json:"deleted_business_messages"`
 }
 
+// UpdateType is the kind of update, named after its field,
+// such as "message" or "poll_answer".
+type UpdateType string
+
+// Type returns the kind of the update, or an empty string
+// if the update carries nothing known.
+func (u Update) Type() UpdateType {
+	switch {
+	case u.Message != nil:
+		return "message"
+	case u.EditedMessage != nil:
+		return "edited_message"
+	case u.ChannelPost != nil:
+		return "channel_post"
+	case u.EditedChannelPost != nil:
+		return "edited_channel_post"
+	case u.BusinessConnection != nil:
+		return "business_connection"
+	case u.BusinessMessage != nil:
+		return "business_message"
+	case u.EditedBusinessMessage != nil:
+		return "edited_business_message"
+	case u.DeletedBusinessMessages != nil:
+		return "deleted_business_messages"
+	case u.MessageReaction != nil:
+		return "message_reaction"
+	case u.MessageReactionCount != nil:
+		return "message_reaction_count"
+	case u.Query != nil:
+		return "inline_query"
+	case u.InlineResult != nil:
+		return "chosen_inline_result"
+	case u.Callback != nil:
+		return "callback_query"
+	case u.ShippingQuery != nil:
+		return "shipping_query"
+	case u.PreCheckoutQuery != nil:
+		return "pre_checkout_query"
+	case u.Poll != nil:
+		return "poll"
+	case u.PollAnswer != nil:
+		return "poll_answer"
+	case u.MyChatMember != nil:
+		return "my_chat_member"
+	case u.ChatMember != nil:
+		return "chat_member"
+	case u.ChatJoinRequest != nil:
+		return "chat_join_request"
+	case u.Boost != nil:
+		return "chat_boost"
+	case u.BoostRemoved != nil:
+		return "removed_chat_boost"
+	default:
+		return ""
+	}
+}
+
 // ProcessUpdate processes a single incoming update.
 // A started bot calls this function automatically.
 func (b *Bot) ProcessUpdate(u Update) {
-	b.ProcessContext(b.NewContext(u))
+	if b.history != nil {
+		b.history.add(u)
+	}
+
+	if !b.synchronous {
+		b.ProcessContext(b.NewContext(u))
+		return
+	}
+
+	// In synchronous mode all the handlers are finished by the time
+	// ProcessContext returns, so the context can be safely reused.
+	c := acquireContext(b, u)
+	b.ProcessContext(c)
+	releaseContext(c)
 }
 
 // ProcessContext processes the given context.
 // A started bot calls this function automatically.
 func (b *Bot) ProcessContext(c Context) {
+	catchAll := b.hasHandler(OnAny)
+	if b.unhandled == nil && b.onUnmatched == nil && !catchAll {
+		b.processContext(c)
+		return
+	}
+
+	// Only the native context tells whether a handler has run
+	nc, ok := c.(*nativeContext)
+	if !ok {
+		b.processContext(c)
+		return
+	}
+
+	nc.handled = false
+	b.processContext(c)
+	if nc.handled || b.handle(OnAny, c) {
+		return
+	}
+
+	if b.unhandled != nil {
+		b.unhandled.add(c.Update().Type())
+	}
+	if b.onUnmatched != nil {
+		b.onUnmatched(c)
+	}
+}
+
+func (b *Bot) processContext(c Context) {
 	u := c.Update()
 
 	if u.Message != nil {
@@ -49,6 +151,15 @@ func (b *Bot) ProcessContext(c Context) {
 			return
 		}
 
+		if m.SuggestedPostInfo != nil && b.handle(OnSuggestedPost, c) {
+			return
+		}
+		// Messages written to a channel direct messages chat are
+		// routed to the usual handlers unless OnDirectMessage is set.
+		if m.Chat != nil && m.Chat.IsDirectMessages() && b.handle(OnDirectMessage, c) {
+			return
+		}
+
 		if m.Origin != nil {
 			b.handle(OnForward, c)
 		}
@@ -60,31 +171,29 @@ func (b *Bot) ProcessContext(c Context) {
 				return
 			}
 
-			match := cmdRx.FindAllStringSubmatch(m.Text, -1)
-			if match != nil {
+			var command string
+			if m.Text[0] == '/' {
 				// Syntax: "</command>@<bot> <payload>"
-				command, botName := match[0][1], match[0][3]
-
-				if botName != "" && !strings.EqualFold(b.Me.Username, botName) {
-					return
-				}
-
-				m.Payload = match[0][5]
-				if b.handle(command, c) {
-					return
+				cmd, botName, payload, ok := SplitCommand(m.Text)
+				if ok {
+					if me := b.Identity(); botName != "" && (me == nil || !strings.EqualFold(me.Username, botName)) {
+						return
+					}
+
+					command = cmd
+					if b.foldCommands {
+						command = strings.ToLower(command)
+					}
+					m.Payload = payload
 				}
 			}
 
-			// 1:1 satisfaction
-			if b.handle(m.Text, c) {
-				return
-			}
-
-			if m.ReplyTo != nil {
-				b.handle(OnReply, c)
-			}
+			b.handleText(c, command)
+			return
+		}
 
-			b.handle(OnText, c)
+		if m.AlbumID != "" && b.bufferAlbum(u) {
+			markHandled(c)
 			return
 		}
 
@@ -152,9 +261,18 @@ func (b *Bot) ProcessContext(c Context) {
 			b.handle(OnWriteAccessAllowed, c)
 			return
 		}
+		if m.SuggestedPostApproved != nil {
+			b.handle(OnSuggestedPostApproved, c)
+			return
+		}
+		if m.SuggestedPostDeclined != nil {
+			b.handle(OnSuggestedPostDeclined, c)
+			return
+		}
 
-		wasAdded := (m.UserJoined != nil && m.UserJoined.ID == b.Me.ID) ||
-			(m.UsersJoined != nil && isUserInList(b.Me, m.UsersJoined))
+		me := b.Identity()
+		wasAdded := me != nil && ((m.UserJoined != nil && m.UserJoined.ID == me.ID) ||
+			(m.UsersJoined != nil && isUserInList(me, m.UsersJoined)))
 		if m.GroupCreated || m.SuperGroupCreated || wasAdded {
 			b.handle(OnAddedToGroup, c)
 			return
@@ -272,19 +390,30 @@ func (b *Bot) ProcessContext(c Context) {
 	}
 
 	if u.Callback != nil {
+		if i := strings.LastIndexByte(u.Callback.Data, '\v'); i >= 0 {
+			u.Callback.Data, u.Callback.stateToken = u.Callback.Data[:i], u.Callback.Data[i+1:]
+		}
 		if data := u.Callback.Data; data != "" && data[0] == '\f' {
-			match := cbackRx.FindAllStringSubmatch(data, -1)
-			if match != nil {
-				unique, payload := match[0][1], match[0][3]
-				if handler, ok := b.handlers["\f"+unique]; ok {
+			if unique, payload, ok := splitCallback(data); ok {
+				// data[:len(unique)+1] is "\f<unique>", avoids concatenation
+				if handler, ok := b.lookup(data[:len(unique)+1], c); ok {
 					u.Callback.Unique = unique
 					u.Callback.Data = payload
 					b.runHandler(handler, c)
 					return
 				}
+				if handler, ok := b.callbackFamily(unique, c); ok {
+					u.Callback.Unique = unique
+					u.Callback.Data = data[1:]
+					b.runHandler(handler, c)
+					return
+				}
 			}
 		}
 
+		if b.handleCallbackPattern(c, u.Callback.Data) {
+			return
+		}
 		b.handle(OnCallback, c)
 		return
 	}
@@ -319,10 +448,12 @@ func (b *Bot) ProcessContext(c Context) {
 	}
 
 	if u.MyChatMember != nil {
+		b.invalidateAdmins(u.MyChatMember)
 		b.handle(OnMyChatMember, c)
 		return
 	}
 	if u.ChatMember != nil {
+		b.invalidateAdmins(u.ChatMember)
 		b.handle(OnChatMember, c)
 		return
 	}
@@ -341,6 +472,7 @@ func (b *Bot) ProcessContext(c Context) {
 	}
 
 	if u.BusinessConnection != nil {
+		b.cacheBusinessConnection(u.BusinessConnection)
 		b.handle(OnBusinessConnection, c)
 		return
 	}
@@ -359,7 +491,7 @@ func (b *Bot) ProcessContext(c Context) {
 }
 
 func (b *Bot) handle(end string, c Context) bool {
-	if handler, ok := b.handlers[end]; ok {
+	if handler, ok := b.lookup(end, c); ok {
 		b.runHandler(handler, c)
 		return true
 	}
@@ -401,8 +533,22 @@ func (b *Bot) handleMedia(c Context) bool {
 }
 
 func (b *Bot) runHandler(h HandlerFunc, c Context) {
+	markHandled(c)
 	f := func() {
-		if err := h(c); err != nil {
+		if b.handlerTimeout > 0 {
+			parent := c.Ctx()
+			ctx, cancel := context.WithTimeout(parent, b.handlerTimeout)
+			c.SetCtx(ctx)
+			defer func() {
+				cancel()
+				c.SetCtx(parent)
+			}()
+		}
+		err := h(c)
+		if nc, ok := c.(*nativeContext); ok {
+			nc.runDeferred(err)
+		}
+		if err != nil && !errors.Is(err, ErrSkip) {
 			b.OnError(err, c)
 		}
 	}
@@ -413,6 +559,99 @@ func (b *Bot) runHandler(h HandlerFunc, c Context) {
 	}
 }
 
+// albumBuffer collects the messages of a single media group.
+type albumBuffer struct {
+	update   Update
+	messages []*Message
+	timer    Timer
+}
+
+// bufferAlbum puts the message of the update into its media group
+// buffer. It returns false if the aggregation is disabled.
+func (b *Bot) bufferAlbum(u Update) bool {
+	if b.albumTimeout <= 0 {
+		return false
+	}
+	if !b.hasHandler(OnAlbum) {
+		return false
+	}
+
+	b.albumsMu.Lock()
+	defer b.albumsMu.Unlock()
+
+	id := u.Message.AlbumID
+
+	buf, ok := b.albums[id]
+	if ok {
+		buf.timer.Reset(b.albumTimeout)
+	} else {
+		buf = &albumBuffer{update: u}
+		buf.timer = b.clock.AfterFunc(b.albumTimeout, func() { b.flushAlbum(id) })
+		b.albums[id] = buf
+	}
+
+	buf.messages = append(buf.messages, u.Message)
+	return true
+}
+
+// flushAlbum handles the buffered media group with OnAlbum handler.
+func (b *Bot) flushAlbum(id string) {
+	b.albumsMu.Lock()
+	buf, ok := b.albums[id]
+	delete(b.albums, id)
+	b.albumsMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	b.handle(OnAlbum, &nativeContext{
+		b:     b,
+		u:     buf.update,
+		album: buf.messages,
+	})
+}
+
+// splitCallback parses the "\f<unique>|<payload>" callback data.
+// It's a hand-written equivalent of the ^\f([-\w]+)(\|(.+))?$
+// expression, which is too expensive for the hot dispatch path.
+func splitCallback(data string) (unique, payload string, ok bool) {
+	unique, payload, piped := strings.Cut(data[1:], "|")
+	if unique == "" || (piped && payload == "") {
+		return "", "", false
+	}
+
+	for i := 0; i < len(unique); i++ {
+		c := unique[i]
+		if !(c == '-' || c == '_' || '0' <= c && c <= '9' ||
+			'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return "", "", false
+		}
+	}
+	if strings.IndexByte(payload, '\n') >= 0 {
+		return "", "", false
+	}
+
+	return unique, payload, true
+}
+
+// callbackFamily finds the handler of the longest unique, which the
+// given one starts with followed by '_' or '-'. So the "page" handler
+// catches the "page_next" and "page_prev" buttons unless they have
+// their own handlers. The handlers restricted to the chat types
+// are included, see lookup.
+func (b *Bot) callbackFamily(unique string, c Context) (HandlerFunc, bool) {
+	for i := len(unique) - 1; i > 0; i-- {
+		if ch := unique[i]; ch != '_' && ch != '-' {
+			continue
+		}
+		if handler, ok := b.lookup("\f"+unique[:i], c); ok {
+			return handler, true
+		}
+	}
+	return nil, false
+}
+
 func isUserInList(user *User, list []User) bool {
 	for _, user2 := range list {
 		if user.ID == user2.ID {
@@ -0,0 +1,63 @@
+package telebot
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MaxVerificationDescription is the maximum length of the custom
+// description of a verification, in characters.
+const MaxVerificationDescription = 70
+
+// VerifyUser verifies the user on behalf of the organization which is
+// represented by the bot. The description is optional, it can only be
+// set if the organization allows it. Returns ErrVerifierForbidden if
+// the bot isn't allowed to verify.
+func (b *Bot) VerifyUser(userID int64, description string) error {
+	params := map[string]string{
+		"user_id": strconv.FormatInt(userID, 10),
+	}
+	return b.verify("verifyUser", params, description)
+}
+
+// VerifyChat verifies the chat on behalf of the organization which is
+// represented by the bot. See VerifyUser.
+func (b *Bot) VerifyChat(chatID int64, description string) error {
+	params := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+	}
+	return b.verify("verifyChat", params, description)
+}
+
+// RemoveUserVerification removes the verification of the user, which
+// is currently verified on behalf of the organization represented by the bot.
+func (b *Bot) RemoveUserVerification(userID int64) error {
+	params := map[string]string{
+		"user_id": strconv.FormatInt(userID, 10),
+	}
+	_, err := b.Raw("removeUserVerification", params)
+	return err
+}
+
+// RemoveChatVerification removes the verification of the chat, which
+// is currently verified on behalf of the organization represented by the bot.
+func (b *Bot) RemoveChatVerification(chatID int64) error {
+	params := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+	}
+	_, err := b.Raw("removeChatVerification", params)
+	return err
+}
+
+func (b *Bot) verify(method string, params map[string]string, description string) error {
+	if n := utf8.RuneCountInString(description); n > MaxVerificationDescription {
+		return fmt.Errorf("telebot: verification description is too long, %d characters out of %d", n, MaxVerificationDescription)
+	}
+	if description != "" {
+		params["custom_description"] = description
+	}
+
+	_, err := b.Raw(method, params)
+	return err
+}
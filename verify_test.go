@@ -0,0 +1,43 @@
+package telebot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	var last apiCall
+	srv := newTestAPI(t, func(call apiCall) string {
+		last = call
+		if call.Params["user_id"] == "2" {
+			return `{"ok":false,"error_code":400,"description":"Bad Request: BOT_VERIFIER_FORBIDDEN"}`
+		}
+		return `{"ok":true,"result":true}`
+	})
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	require.NoError(t, b.VerifyUser(1, "Staff"))
+	assert.Equal(t, "verifyUser", last.Method)
+	assert.Equal(t, map[string]string{"user_id": "1", "custom_description": "Staff"}, last.Params)
+
+	require.NoError(t, b.VerifyChat(-100, ""))
+	assert.Equal(t, "verifyChat", last.Method)
+	assert.Equal(t, map[string]string{"chat_id": "-100"}, last.Params)
+
+	require.NoError(t, b.RemoveUserVerification(1))
+	assert.Equal(t, "removeUserVerification", last.Method)
+	require.NoError(t, b.RemoveChatVerification(-100))
+	assert.Equal(t, "removeChatVerification", last.Method)
+
+	assert.ErrorIs(t, b.VerifyUser(2, ""), ErrVerifierForbidden)
+
+	last = apiCall{}
+	assert.ErrorContains(t, b.VerifyUser(1, strings.Repeat("я", MaxVerificationDescription+1)), "too long")
+	assert.Empty(t, last.Method)
+	assert.NoError(t, b.VerifyUser(1, strings.Repeat("я", MaxVerificationDescription)))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // A WebhookTLS specifies the path to a key and a cert so the poller can open
@@ -130,6 +132,8 @@ func (h *Webhook) Poll(b *Bot, dest chan Update, stop chan struct{}) {
 		}
 	}
 
+	b.checkWebhook()
+
 	// store the variables so the HTTP-handler can use 'em
 	h.dest = dest
 	h.bot = b
@@ -169,11 +173,18 @@ func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.bot.debug(fmt.Errorf("cannot read update: %v", err))
+		return
+	}
+
 	var update Update
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+	if err := json.Unmarshal(data, &update); err != nil {
 		h.bot.debug(fmt.Errorf("cannot decode update: %v", err))
 		return
 	}
+	h.bot.dump.update(update, data)
 	h.dest <- update
 }
 
@@ -193,9 +204,82 @@ func (b *Bot) Webhook() (*Webhook, error) {
 	return &resp.Result, nil
 }
 
+// WebhookInfo describes the current status of a webhook.
+type WebhookInfo struct {
+	// Webhook URL, may be empty if webhook is not set up.
+	URL string `json:"url"`
+
+	// True, if a custom certificate was provided for webhook certificate checks.
+	HasCustomCert bool `json:"has_custom_certificate"`
+
+	// Number of updates awaiting delivery.
+	PendingUpdates int `json:"pending_update_count"`
+
+	// (Optional) Currently used webhook IP address.
+	IP string `json:"ip_address,omitempty"`
+
+	// (Optional) Unixtime of the most recent error that happened
+	// when trying to deliver an update via webhook.
+	LastErrorUnixtime int64 `json:"last_error_date,omitempty"`
+
+	// (Optional) Error message in human-readable format for the most recent
+	// error that happened when trying to deliver an update via webhook.
+	LastErrorMessage string `json:"last_error_message,omitempty"`
+
+	// (Optional) Unixtime of the most recent error that happened when trying
+	// to synchronize available updates with Telegram datacenters.
+	LastSyncErrorUnixtime int64 `json:"last_synchronization_error_date,omitempty"`
+
+	// (Optional) The maximum allowed number of simultaneous
+	// HTTPS connections to the webhook for update delivery.
+	MaxConnections int `json:"max_connections,omitempty"`
+
+	// (Optional) A list of update types the bot is subscribed to.
+	// Defaults to all update types except chat_member.
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// LastErrorDate returns the time of the most recent delivery error in local time.
+func (w *WebhookInfo) LastErrorDate() time.Time {
+	return time.Unix(w.LastErrorUnixtime, 0)
+}
+
+// WebhookInfo returns the current webhook status. It works regardless
+// of the poller used, URL is empty if no webhook is set up.
+func (b *Bot) WebhookInfo() (*WebhookInfo, error) {
+	data, err := b.Raw("getWebhookInfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Result *WebhookInfo
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Result, nil
+}
+
+// checkWebhook warns about the webhook delivery errors
+// reported by Telegram, so they don't stay unnoticed.
+func (b *Bot) checkWebhook() {
+	info, err := b.WebhookInfo()
+	if err != nil {
+		b.Logger("webhook").Warn("Failed to get webhook info: %v", err)
+		return
+	}
+	if info.LastErrorMessage != "" {
+		b.Logger("webhook").Warn("Webhook delivery failed at %s: %s (%d pending updates)",
+			info.LastErrorDate().Format(time.RFC3339), info.LastErrorMessage, info.PendingUpdates)
+	}
+}
+
 // SetWebhook configures a bot to receive incoming
 // updates via an outgoing webhook.
 func (b *Bot) SetWebhook(w *Webhook) error {
+	b.redactor.add(w.SecretToken)
+
 	_, err := b.sendFiles("setWebhook", w.getFiles(), w.getParams())
 	return err
 }